@@ -1,7 +1,7 @@
 package app
 
 import (
-	"math/rand/v2"
+	"context"
 	"time"
 
 	"github.com/mlange-42/ark-tools/resource"
@@ -16,14 +16,18 @@ import (
 // [UISystem] instances are updated independently of normal systems,
 // with a frequency given by FPS.
 //
-// The [Systems] scheduler, the app's [resource.Tick], [resource.Termination]
-// and a central [resource.Rand] PRNG source can be accessed by systems as resources.
+// The [Systems] scheduler, the app's [resource.Tick], [resource.ModelTime], [resource.Termination], [resource.Interpolation],
+// the [Profile] and a central [resource.Rand] PRNG source can be accessed by systems as resources.
 type App struct {
 	Systems             // Systems manager and scheduler
 	World     ecs.World // The ECS world
 	rand      resource.Rand
 	time      resource.Tick
 	terminate resource.Termination
+	modelTime resource.ModelTime
+	interp    resource.Interpolation
+	profile   Profile
+	plugins   []Plugin
 }
 
 // New creates a new app.
@@ -37,14 +41,18 @@ func New(initialCapacity ...int) *App {
 	app.TPS = 0
 	app.Systems.world = &app.World
 
-	app.rand = resource.Rand{
-		Source: rand.NewPCG(0, uint64(time.Now().UnixNano())),
-	}
+	app.rand = resource.NewRand(uint64(time.Now().UnixNano()))
 	ecs.AddResource(&app.World, &app.rand)
 	app.time = resource.Tick{}
 	ecs.AddResource(&app.World, &app.time)
 	app.terminate = resource.Termination{}
 	ecs.AddResource(&app.World, &app.terminate)
+	app.modelTime = resource.ModelTime{Step: 1}
+	ecs.AddResource(&app.World, &app.modelTime)
+	app.interp = resource.Interpolation{}
+	ecs.AddResource(&app.World, &app.interp)
+	app.profile = Profile{}
+	ecs.AddResource(&app.World, &app.profile)
 
 	ecs.AddResource(&app.World, &app.Systems)
 
@@ -53,14 +61,15 @@ func New(initialCapacity ...int) *App {
 
 // Seed sets the random seed of the app's [resource.Rand].
 // Call without an argument to seed from the current time.
+// The seed used can be queried via [resource.Rand.Seed].
 //
 // Systems should always use the Rand resource for PRNGs.
 func (app *App) Seed(seed ...uint64) *App {
 	switch len(seed) {
 	case 0:
-		app.rand.Source = rand.NewPCG(0, uint64(time.Now().UnixNano()))
+		app.rand = resource.NewRand(uint64(time.Now().UnixNano()))
 	case 1:
-		app.rand.Source = rand.NewPCG(0, seed[0])
+		app.rand = resource.NewRand(seed[0])
 	default:
 		panic("can only use a single random seed")
 	}
@@ -73,6 +82,7 @@ func (app *App) Seed(seed ...uint64) *App {
 //
 // Runs until Terminate in the resource resource.Termination is set to true
 // (see [resource.Termination]).
+// The reason for termination can be retrieved with [App.Termination] after the run.
 //
 // To perform updates manually, see [App.Update] and [App.UpdateUI],
 // as well as [App.Initialize] and [App.Finalize].
@@ -80,6 +90,36 @@ func (app *App) Run() {
 	app.Systems.run()
 }
 
+// Termination returns the current state of the termination resource,
+// including the reason for termination after a run.
+func (app *App) Termination() resource.Termination {
+	return app.terminate
+}
+
+// RunE runs the app like [App.Run], but returns an error instead of panicking.
+//
+// Errors are returned by systems that implement the optional [InitializerE],
+// [UpdaterE] or [FinalizerE] interfaces, or by the scheduler itself,
+// e.g. when removing a system that is not in the app.
+// Panics in system updates are converted to errors, with sequential as well as concurrent updates.
+// The returned error names the failing system and the tick.
+//
+// On error, the run is stopped, and all systems that were initialized are finalized.
+// The reason for regular termination can be obtained from the [resource.Termination] resource.
+func (app *App) RunE() error {
+	return app.Systems.runE()
+}
+
+// RunContext runs the app like [App.RunE], but also stops when the context is cancelled.
+//
+// The app is finalized also on cancellation, so that e.g. reporters can flush and close their files.
+// Returns the context's error if the run was stopped due to cancellation.
+//
+// See [InterruptContext] for stopping a run on SIGINT (Ctrl+C) or SIGTERM.
+func (app *App) RunContext(ctx context.Context) error {
+	return app.Systems.runContext(ctx)
+}
+
 // Initialize the app.
 func (app *App) Initialize() {
 	app.Systems.initialize()
@@ -109,22 +149,27 @@ func (app *App) Finalize() {
 	app.Systems.finalize()
 }
 
-// Reset resets the world and removes all systems.
+// Reset resets the world and removes all systems and plugins.
 //
 // Can be used to run systematic simulations without the need to re-allocate memory for each run.
 // Accelerates re-populating the world by a factor of 2-3.
 func (app *App) Reset() {
 	app.World.Reset()
 	app.Systems.reset()
+	app.plugins = app.plugins[:0]
 
-	app.rand = resource.Rand{
-		Source: rand.NewPCG(0, uint64(time.Now().UnixNano())),
-	}
+	app.rand = resource.NewRand(uint64(time.Now().UnixNano()))
 	ecs.AddResource(&app.World, &app.rand)
 	app.time = resource.Tick{}
 	ecs.AddResource(&app.World, &app.time)
 	app.terminate = resource.Termination{}
 	ecs.AddResource(&app.World, &app.terminate)
+	app.modelTime = resource.ModelTime{Step: 1}
+	ecs.AddResource(&app.World, &app.modelTime)
+	app.interp = resource.Interpolation{}
+	ecs.AddResource(&app.World, &app.interp)
+	app.profile = Profile{}
+	ecs.AddResource(&app.World, &app.profile)
 
 	ecs.AddResource(&app.World, &app.Systems)
 }
@@ -1,6 +1,7 @@
 package app_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/mlange-42/ark-tools/app"
@@ -52,6 +53,54 @@ func TestAppStep(t *testing.T) {
 	}
 }
 
+func TestAppRunE(t *testing.T) {
+	app := app.New(1024)
+
+	first := errorSystem{FailAt: -1}
+	failing := errorSystem{FailAt: 5}
+	last := errorSystem{FailAt: -1}
+	app.AddSystem(&first)
+	app.AddSystem(&failing)
+	app.AddSystem(&last)
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+
+	err := app.RunE()
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorContains(t, err, "updating system *app_test.errorSystem at tick 5")
+	assert.True(t, first.Finalized)
+	assert.True(t, failing.Finalized)
+	assert.True(t, last.Finalized)
+}
+
+func TestAppRunEInitialize(t *testing.T) {
+	app := app.New(1024)
+
+	first := errorSystem{FailAt: -1}
+	failing := errorSystem{FailAt: -1, FailInit: true}
+	last := errorSystem{FailAt: -1}
+	app.AddSystem(&first)
+	app.AddSystem(&failing)
+	app.AddSystem(&last)
+
+	err := app.RunE()
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorContains(t, err, "initializing system *app_test.errorSystem at tick 0")
+	assert.True(t, first.Finalized)
+	assert.False(t, failing.Finalized)
+	assert.False(t, last.Finalized)
+}
+
+func TestAppRunEOk(t *testing.T) {
+	app := app.New(1024)
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+	assert.Nil(t, app.RunE())
+
+	app.Reset()
+	app.AddSystem(&errorSystem{FailAt: 3})
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+	assert.Panics(t, func() { app.Run() })
+}
+
 func TestAppSeed(t *testing.T) {
 	app := app.New(1024)
 	app.Seed(123)
@@ -123,3 +172,37 @@ func ExampleApp_Reset() {
 	}
 	// Output:
 }
+
+var errTest = errors.New("test error")
+
+// errorSystem is a system that fails on initialization or at a given tick.
+type errorSystem struct {
+	FailInit  bool
+	FailAt    int64
+	Finalized bool
+	tickRes   ecs.Resource[resource.Tick]
+}
+
+func (s *errorSystem) Initialize(w *ecs.World) {}
+func (s *errorSystem) Update(w *ecs.World)     {}
+func (s *errorSystem) Finalize(w *ecs.World)   {}
+
+func (s *errorSystem) InitializeE(w *ecs.World) error {
+	s.tickRes = ecs.NewResource[resource.Tick](w)
+	if s.FailInit {
+		return errTest
+	}
+	return nil
+}
+
+func (s *errorSystem) UpdateE(w *ecs.World) error {
+	if s.tickRes.Get().Tick == s.FailAt {
+		return errTest
+	}
+	return nil
+}
+
+func (s *errorSystem) FinalizeE(w *ecs.World) error {
+	s.Finalized = true
+	return nil
+}
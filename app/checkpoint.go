@@ -0,0 +1,227 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// checkpoint is the serialized state of an [App].
+type checkpoint struct {
+	Tick        resource.Tick
+	Termination resource.Termination
+	Rand        resource.Rand
+	Entities    ecs.EntityDump
+	Types       []string
+	Components  []entityComponents
+	Resources   map[string]json.RawMessage
+}
+
+// entityComponents holds the serialized components of an entity.
+type entityComponents struct {
+	Entity  ecs.Entity        `json:"e"`
+	Types   []int             `json:"t"`
+	Targets []ecs.Entity      `json:"r,omitempty"`
+	Values  []json.RawMessage `json:"c"`
+}
+
+// Save writes the state of the app to a writer, for resuming a run later via [App.Load].
+//
+// Saves all entities and their components, as well as all resources, in JSON format.
+// This includes the app's [resource.Tick], [resource.Termination] and the state of the [resource.Rand] PRNG.
+// Excluded are the [Systems], [Profile] and [resource.Interpolation] resources.
+// Components and resources must be serializable with [encoding/json].
+//
+// The state of systems is not saved. Systems that keep state between updates
+// must store it in resources or components to be restored.
+// Should only be called between updates.
+func (app *App) Save(w io.Writer) error {
+	data := checkpoint{
+		Tick:        app.time,
+		Termination: app.terminate,
+		Rand:        app.rand,
+		Entities:    app.World.Unsafe().DumpEntities(),
+		Resources:   map[string]json.RawMessage{},
+	}
+
+	ids := ecs.ComponentIDs(&app.World)
+	types := make(map[ecs.ID]int, len(ids))
+	for _, id := range ids {
+		info, _ := ecs.ComponentInfo(&app.World, id)
+		types[id] = len(data.Types)
+		data.Types = append(data.Types, typeName(info.Type))
+	}
+
+	u := app.World.Unsafe()
+	filter := ecs.NewUnsafeFilter(&app.World)
+	query := filter.Query()
+	for query.Next() {
+		entity := query.Entity()
+		comps := entityComponents{Entity: entity}
+		entityIDs := u.IDs(entity)
+		for i := range entityIDs.Len() {
+			id := entityIDs.Get(i)
+			info, _ := ecs.ComponentInfo(&app.World, id)
+			value, err := json.Marshal(reflect.NewAt(info.Type, query.Get(id)).Interface())
+			if err != nil {
+				query.Close()
+				return fmt.Errorf("saving component %s: %w", info.Type, err)
+			}
+			comps.Types = append(comps.Types, types[id])
+			comps.Values = append(comps.Values, value)
+			if info.IsRelation {
+				comps.Targets = append(comps.Targets, u.GetRelation(entity, id))
+			}
+		}
+		data.Components = append(data.Components, comps)
+	}
+
+	for _, id := range ecs.ResourceIDs(&app.World) {
+		tp, _ := ecs.ResourceType(&app.World, id)
+		if app.isInternalResource(tp) || !app.World.Resources().Has(id) {
+			continue
+		}
+		value, err := json.Marshal(app.World.Resources().Get(id))
+		if err != nil {
+			return fmt.Errorf("saving resource %s: %w", tp, err)
+		}
+		data.Resources[typeName(tp)] = value
+	}
+
+	return json.NewEncoder(w).Encode(&data)
+}
+
+// Load restores the state of the app from a reader, as written by [App.Save].
+//
+// Replaces all entities by the loaded ones, and restores the values of resources.
+// Initializes the app if it is not already initialized,
+// as this is where systems register the component and resource types to be loaded.
+// The app should have the same systems as the one that was saved.
+//
+// Only the world's state, i.e. entities, components and resources, is restored.
+// Systems are initialized anew, and state kept in their own fields is not restored.
+// A continued run thus proceeds exactly like the original run only for systems that keep their state in the world.
+// This is not the case for the reporters and [github.com/mlange-42/ark-tools/system.PerfTimer],
+// which count their own steps from initialization.
+// Further, [github.com/mlange-42/ark-tools/reporter.CSV] overwrites its output file,
+// so it should be given a different file name when resuming.
+//
+// Returns an error if the data contains components or resources not present in the app.
+// Errors in component or resource data may leave the app in a partially loaded state.
+func (app *App) Load(r io.Reader) error {
+	data := checkpoint{}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	if !app.Systems.initialized {
+		if err := app.Systems.initializeE(); err != nil {
+			return err
+		}
+	}
+
+	types := map[string]ecs.ID{}
+	for _, id := range ecs.ComponentIDs(&app.World) {
+		info, _ := ecs.ComponentInfo(&app.World, id)
+		types[typeName(info.Type)] = id
+	}
+	ids := make([]ecs.ID, len(data.Types))
+	for i, name := range data.Types {
+		id, ok := types[name]
+		if !ok {
+			return fmt.Errorf("component type %s is not registered in the app", name)
+		}
+		ids[i] = id
+	}
+
+	resources := map[string]ecs.ResID{}
+	for _, id := range ecs.ResourceIDs(&app.World) {
+		if !app.World.Resources().Has(id) {
+			continue
+		}
+		tp, _ := ecs.ResourceType(&app.World, id)
+		resources[typeName(tp)] = id
+	}
+	for name := range data.Resources {
+		if _, ok := resources[name]; !ok {
+			return fmt.Errorf("resource type %s is not present in the app", name)
+		}
+	}
+
+	// Resetting the world removes all resources, so they are re-added afterwards.
+	saved := make([]any, len(resources))
+	savedIDs := make([]ecs.ResID, 0, len(resources))
+	for _, id := range resources {
+		saved[len(savedIDs)] = app.World.Resources().Get(id)
+		savedIDs = append(savedIDs, id)
+	}
+	app.World.Reset()
+	for i, id := range savedIDs {
+		app.World.Resources().Add(id, saved[i])
+	}
+
+	u := app.World.Unsafe()
+	u.LoadEntities(&data.Entities)
+
+	for _, comps := range data.Components {
+		if len(comps.Types) == 0 {
+			continue
+		}
+		compIDs := make([]ecs.ID, len(comps.Types))
+		relations := []ecs.RelationID{}
+		for i, idx := range comps.Types {
+			compIDs[i] = ids[idx]
+			if info, _ := ecs.ComponentInfo(&app.World, compIDs[i]); info.IsRelation {
+				relations = append(relations, ecs.RelID(compIDs[i], comps.Targets[len(relations)]))
+			}
+		}
+		u.AddRel(comps.Entity, compIDs, relations...)
+		for i, id := range compIDs {
+			info, _ := ecs.ComponentInfo(&app.World, id)
+			if err := unmarshalAt(comps.Values[i], info.Type, u.Get(comps.Entity, id)); err != nil {
+				return fmt.Errorf("loading component %s: %w", info.Type, err)
+			}
+		}
+	}
+
+	for name, value := range data.Resources {
+		if err := json.Unmarshal(value, app.World.Resources().Get(resources[name])); err != nil {
+			return fmt.Errorf("loading resource %s: %w", name, err)
+		}
+	}
+
+	app.rand = data.Rand
+	app.time = data.Tick
+	app.terminate = data.Termination
+	return nil
+}
+
+// isInternalResource returns whether a resource type is managed by the app,
+// and thus excluded from generic (de)-serialization.
+func (app *App) isInternalResource(tp reflect.Type) bool {
+	switch tp {
+	case reflect.TypeFor[Systems](), reflect.TypeFor[Profile](),
+		reflect.TypeFor[resource.Interpolation](), reflect.TypeFor[resource.Rand](),
+		reflect.TypeFor[resource.Tick](), reflect.TypeFor[resource.Termination]():
+		return true
+	}
+	return false
+}
+
+// unmarshalAt unmarshals JSON data into the value of the given type at a pointer.
+func unmarshalAt(data []byte, tp reflect.Type, ptr unsafe.Pointer) error {
+	return json.Unmarshal(data, reflect.NewAt(tp, ptr).Interface())
+}
+
+// typeName returns the fully qualified name of a type.
+func typeName(tp reflect.Type) string {
+	if tp.PkgPath() == "" {
+		return tp.String()
+	}
+	return tp.PkgPath() + "." + tp.Name()
+}
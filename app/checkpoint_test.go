@@ -0,0 +1,127 @@
+package app_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type walkPosition struct {
+	X, Y float64
+}
+
+type walkParent struct {
+	ecs.RelationMarker
+}
+
+type walkStats struct {
+	Moves int
+}
+
+// walkSystem moves entities randomly, and removes and creates entities.
+type walkSystem struct {
+	filter  *ecs.Filter1[walkPosition]
+	mapper  *ecs.Map2[walkPosition, walkParent]
+	rand    ecs.Resource[resource.Rand]
+	stats   ecs.Resource[walkStats]
+	toClear []ecs.Entity
+}
+
+func (s *walkSystem) Initialize(w *ecs.World) {
+	s.filter = ecs.NewFilter1[walkPosition](w)
+	s.mapper = ecs.NewMap2[walkPosition, walkParent](w)
+	s.rand = ecs.NewResource[resource.Rand](w)
+	s.stats = ecs.NewResource[walkStats](w)
+	ecs.AddResource(w, &walkStats{})
+
+	parent := w.NewEntity()
+	s.mapper.NewBatchFn(10, nil, ecs.Rel[walkParent](parent))
+}
+
+func (s *walkSystem) Update(w *ecs.World) {
+	rng := s.rand.Get()
+	stats := s.stats.Get()
+	query := s.filter.Query()
+	for query.Next() {
+		pos := query.Get()
+		pos.X += float64(rng.Uint64()%100) / 100
+		pos.Y -= float64(rng.Uint64()%100) / 100
+		stats.Moves++
+		if rng.Uint64()%20 == 0 {
+			s.toClear = append(s.toClear, query.Entity())
+		}
+	}
+	for _, e := range s.toClear {
+		w.RemoveEntity(e)
+		s.mapper.NewEntity(&walkPosition{}, &walkParent{}, ecs.Rel[walkParent](ecs.Entity{}))
+	}
+	s.toClear = s.toClear[:0]
+}
+
+func (s *walkSystem) Finalize(w *ecs.World) {}
+
+func newWalkApp() *app.App {
+	a := app.New(1024).Seed(42)
+	a.AddSystem(&walkSystem{})
+	return a
+}
+
+func walkState(a *app.App) ([]walkPosition, walkStats, uint64) {
+	positions := []walkPosition{}
+	query := ecs.NewFilter1[walkPosition](&a.World).Query()
+	for query.Next() {
+		positions = append(positions, *query.Get())
+	}
+	return positions, *ecs.GetResource[walkStats](&a.World), ecs.GetResource[resource.Rand](&a.World).Uint64()
+}
+
+func TestAppSaveLoad(t *testing.T) {
+	reference := newWalkApp()
+	reference.AddSystem(&system.FixedTermination{Steps: 100})
+	reference.Run()
+
+	first := newWalkApp()
+	first.Initialize()
+	for range 50 {
+		first.Update()
+	}
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, first.Save(&buf))
+
+	resumed := newWalkApp().Seed(1)
+	resumed.AddSystem(&system.FixedTermination{Steps: 100})
+	assert.Nil(t, resumed.Load(&buf))
+	assert.Equal(t, int64(50), ecs.GetResource[resource.Tick](&resumed.World).Tick)
+	resumed.Run()
+
+	refPos, refStats, refRand := walkState(reference)
+	pos, stats, rand := walkState(resumed)
+	assert.Equal(t, refPos, pos)
+	assert.Equal(t, refStats, stats)
+	assert.Equal(t, refRand, rand)
+	assert.Equal(t, int64(100), ecs.GetResource[resource.Tick](&resumed.World).Tick)
+}
+
+func TestAppLoadErrors(t *testing.T) {
+	first := newWalkApp()
+	first.AddSystem(&system.FixedTermination{Steps: 10})
+	first.Run()
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, first.Save(&buf))
+	data := buf.Bytes()
+
+	other := app.New(1024)
+	other.AddSystem(&system.FixedTermination{Steps: 10})
+	err := other.Load(bytes.NewReader(data))
+	assert.ErrorContains(t, err, "component type github.com/mlange-42/ark-tools/app_test.walkPosition is not registered in the app")
+
+	err = newWalkApp().Load(bytes.NewReader([]byte("{")))
+	assert.ErrorContains(t, err, "reading checkpoint")
+}
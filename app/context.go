@@ -0,0 +1,17 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// InterruptContext returns a copy of the parent context that is cancelled
+// when the process receives SIGINT (e.g. by Ctrl+C) or SIGTERM.
+//
+// Use it with [App.RunContext] to stop a simulation gracefully, without losing output.
+// Call the returned stop function to release resources and restore the default signal behavior.
+func InterruptContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
@@ -0,0 +1,94 @@
+package app_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppRunContext(t *testing.T) {
+	app := app.New(1024)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sys := errorSystem{FailAt: -1}
+	app.AddSystem(&sys)
+	app.AddSystem(&system.CallbackTermination{
+		Callback: func(t int64) bool {
+			if t == 5 {
+				cancel()
+			}
+			return false
+		},
+	})
+
+	err := app.RunContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, sys.Finalized)
+
+	tick := ecs.GetResource[resource.Tick](&app.World)
+	assert.Equal(t, int64(6), tick.Tick)
+
+	err = app.RunContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAppRunContextWait(t *testing.T) {
+	app := app.New(1024)
+	app.TPS = 1
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+
+	start := time.Now()
+	err := app.RunContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestInterruptContext(t *testing.T) {
+	ctx, stop := app.InterruptContext(context.Background())
+	defer stop()
+
+	proc, err := os.FindProcess(os.Getpid())
+	assert.Nil(t, err)
+	if err := proc.Signal(os.Interrupt); err != nil {
+		t.Skip("sending interrupt is not supported on this platform")
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled on interrupt")
+	}
+}
+
+func ExampleInterruptContext() {
+	// Create a new, seeded app.
+	myApp := app.New(1024).Seed(123)
+
+	// Add systems.
+	myApp.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+
+	// Create a context that is cancelled on Ctrl+C.
+	ctx, stop := app.InterruptContext(context.Background())
+	defer stop()
+
+	// Run the simulation until it terminates or is interrupted.
+	if err := myApp.RunContext(ctx); err != nil {
+		panic(err)
+	}
+	// Output:
+}
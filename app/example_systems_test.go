@@ -1,7 +1,10 @@
 package app_test
 
 import (
+	"fmt"
+
 	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/reporter"
 	"github.com/mlange-42/ark-tools/system"
 	"github.com/mlange-42/ark/ecs"
 )
@@ -33,3 +36,70 @@ func ExampleSystems() {
 	systems.RemoveSystem(&sys)
 	// Output:
 }
+
+func ExampleAfter() {
+	// Create a new model.
+	myApp := app.New(1024)
+
+	// Create systems, e.g. from different packages.
+	term := system.FixedTermination{Steps: 10}
+	timer := system.PerfTimer{UpdateInterval: 100}
+
+	// The termination system is added first, but updated in the observation stage.
+	myApp.AddSystem(&term, app.InStage(app.StageObserve))
+	// The timer must be updated after the termination system.
+	myApp.AddSystem(&timer, app.After(&term), app.InStage(app.StageObserve))
+	// Added last, but updated first.
+	myApp.AddSystem(&system.CallbackTermination{
+		Callback: func(t int64) bool { return false },
+	})
+
+	// Systems are sorted during initialization.
+	myApp.Initialize()
+
+	for _, sys := range myApp.Systems.Systems() {
+		fmt.Printf("%T\n", sys)
+	}
+	// Output:
+	// *system.CallbackTermination
+	// *system.FixedTermination
+	// *system.PerfTimer
+}
+
+func ExampleWithInterval() {
+	// Create a new model with daily ticks.
+	myApp := app.New(1024)
+
+	// Add a yearly process, starting in the middle of the first year.
+	myApp.AddSystem(&system.PerfTimer{UpdateInterval: 1}, app.WithInterval(365, 182))
+
+	// Add a termination system that ends the simulation after 10 years.
+	myApp.AddSystem(&system.FixedTermination{Steps: 3650})
+
+	// Uncomment the next line.
+
+	// myApp.Run()
+	// Output:
+}
+
+func ExampleProfileObserver() {
+	// Create a new model.
+	myApp := app.New(1024)
+
+	// Enable profiling of systems.
+	myApp.Profiling = true
+
+	// Add a reporter for the profiling statistics.
+	myApp.AddSystem(&reporter.Print{
+		Observer:       &app.ProfileObserver{Percentiles: []float64{95}},
+		UpdateInterval: 100,
+	}, app.InStage(app.StageObserve))
+
+	// Add a termination system that ends the simulation.
+	myApp.AddSystem(&system.FixedTermination{Steps: 1000})
+
+	// Uncomment the next line.
+
+	// myApp.Run()
+	// Output:
+}
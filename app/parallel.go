@@ -0,0 +1,161 @@
+package app
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Access declares the components and resources a [System] reads and writes.
+//
+// See [AccessSystem].
+type Access struct {
+	Read           []ecs.ID    // Components the system reads.
+	Write          []ecs.ID    // Components the system writes.
+	ReadResources  []ecs.ResID // Resources the system reads.
+	WriteResources []ecs.ResID // Resources the system writes.
+}
+
+// conflicts returns whether two accesses can't be performed concurrently.
+func (a *Access) conflicts(other *Access) bool {
+	return overlaps(a.Write, other.Read) || overlaps(a.Write, other.Write) || overlaps(a.Read, other.Write) ||
+		overlaps(a.WriteResources, other.ReadResources) || overlaps(a.WriteResources, other.WriteResources) ||
+		overlaps(a.ReadResources, other.WriteResources)
+}
+
+// AccessSystem is an optional interface for a [System] that declares
+// which components and resources it reads and writes.
+//
+// If [Systems.Parallel] is enabled, systems of the same [Stage] are updated concurrently
+// if their declared accesses don't conflict, and if they have no ordering constraints between them.
+// Systems that don't implement AccessSystem are never updated concurrently with other systems.
+//
+// Systems that are updated concurrently must not perform structural changes to the world,
+// like creating or removing entities, or adding or removing components.
+// Adding, removing, enabling and disabling systems is safe, as it takes effect only after the update step.
+// Systems that use the [resource.Rand] PRNG must declare a write access to it.
+//
+// Note that the Ark world is not safe for concurrent access, even if systems only read
+// and touch disjoint sets of components, as queries modify the world's internal state.
+// Systems that are updated concurrently must therefore create and iterate queries inside [Systems.Sync],
+// which runs them one after another. Nothing detects a missing Sync; it results in a data race.
+// Consequently, only work that does not access the world actually runs in parallel,
+// like computations on data previously collected inside Sync.
+// Declared accesses only determine which systems may be updated concurrently.
+type AccessSystem interface {
+	// Access declared by the system.
+	// Called when the concurrent schedule is planned, i.e. on the first update,
+	// and again after systems were added or removed.
+	Access(w *ecs.World) Access
+}
+
+// planParallel groups the systems into batches that can be updated concurrently.
+// Batches are updated one after another.
+func (s *Systems) planParallel() {
+	accesses := make([]*Access, len(s.systems))
+	for i, sys := range s.systems {
+		if sys, ok := sys.(AccessSystem); ok {
+			access := sys.Access(s.world)
+			accesses[i] = &access
+		}
+	}
+
+	levels := make([]int, len(s.systems))
+	s.batches = s.batches[:0]
+	start := 0
+	for i, sys := range s.systems {
+		info := &s.infos[i]
+		if i > 0 && info.stage != s.infos[i-1].stage {
+			start = len(s.batches)
+		}
+		level := start
+		for j := i - 1; j >= 0; j-- {
+			other := s.systems[j]
+			otherInfo := &s.infos[j]
+			if otherInfo.stage != info.stage {
+				break
+			}
+			if levels[j] >= level && s.dependent(sys, info, accesses[i], other, otherInfo, accesses[j]) {
+				level = levels[j] + 1
+			}
+		}
+		levels[i] = level
+		if level == len(s.batches) {
+			s.batches = append(s.batches, nil)
+		}
+		s.batches[level] = append(s.batches[level], i)
+	}
+	s.planned = true
+}
+
+// dependent returns whether two systems must not be updated concurrently.
+func (s *Systems) dependent(a System, aInfo *systemInfo, aAccess *Access, b System, bInfo *systemInfo, bAccess *Access) bool {
+	if aAccess == nil || bAccess == nil {
+		return true
+	}
+	if slices.Contains(aInfo.before, b) || slices.Contains(aInfo.after, b) ||
+		slices.Contains(bInfo.before, a) || slices.Contains(bInfo.after, a) {
+		return true
+	}
+	return aAccess.conflicts(bAccess)
+}
+
+// updateSystemsParallel updates batches of independent systems concurrently.
+func (s *Systems) updateSystemsParallel() (err error) {
+	if !s.planned {
+		s.planParallel()
+	}
+	var current System
+	defer s.recoverUpdate(&current, &err)
+
+	tick := s.tickRes.Get().Tick
+	for _, batch := range s.batches {
+		if len(batch) == 1 {
+			current = s.systems[batch[0]]
+			if err := s.updateSystem(current, &s.infos[batch[0]], tick); err != nil {
+				return err
+			}
+			continue
+		}
+		errs := make([]error, len(batch))
+		var wg sync.WaitGroup
+		for i, idx := range batch {
+			sys := s.systems[idx]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Panics can't be recovered outside of the goroutine.
+				defer s.recoverUpdate(&sys, &errs[i])
+				errs[i] = s.updateSystem(sys, &s.infos[idx], tick)
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Sync runs a function exclusively, i.e. never concurrently with other functions passed to Sync.
+// Systems that are updated concurrently must use it for all world access, including queries.
+// Functions passed to Sync are serialized globally, also for systems with disjoint component access.
+// See [AccessSystem] for details.
+func (s *Systems) Sync(fn func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fn()
+}
+
+// overlaps returns whether two slices have a common element.
+func overlaps[T comparable](a, b []T) bool {
+	for _, v := range a {
+		if slices.Contains(b, v) {
+			return true
+		}
+	}
+	return false
+}
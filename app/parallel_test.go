@@ -0,0 +1,180 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type position struct {
+	X float64
+}
+
+type velocity struct {
+	X float64
+}
+
+func TestSystemsParallel(t *testing.T) {
+	app := New(1024)
+	app.Parallel = true
+
+	move1 := &accessSystem{Write: true}
+	move2 := &accessSystem{Write: true}
+	read1 := &accessSystem{}
+	read2 := &accessSystem{}
+	other := &counterSystem{}
+	term := &system.FixedTermination{Steps: 100}
+
+	app.AddSystem(read1)
+	app.AddSystem(read2)
+	app.AddSystem(move1)
+	app.AddSystem(move2, After(read1))
+	app.AddSystem(other)
+	app.AddSystem(term, InStage(StageObserve))
+
+	app.Run()
+
+	expected := [][]System{{read1, read2}, {move1}, {move2}, {other}, {term}}
+	assert.Equal(t, len(expected), len(app.batches))
+	for i, batch := range expected {
+		assert.Equal(t, len(batch), len(app.batches[i]))
+		for j, sys := range batch {
+			assert.Same(t, sys, app.systems[app.batches[i][j]])
+		}
+	}
+	assert.Equal(t, 100, read1.Updates)
+	assert.Equal(t, 100, move2.Updates)
+
+	filter := ecs.NewFilter1[position](&app.World)
+	query := filter.Query()
+	for query.Next() {
+		assert.Equal(t, 200.0, query.Get().X)
+	}
+}
+
+func TestSystemsParallelError(t *testing.T) {
+	app := New(1024)
+	app.Parallel = true
+
+	app.AddSystem(&accessSystem{})
+	app.AddSystem(&accessSystem{FailAt: 10})
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+
+	err := app.RunE()
+	assert.ErrorIs(t, err, errAccess)
+	assert.ErrorContains(t, err, "updating system *app.accessSystem at tick 10")
+}
+
+func TestSystemsParallelPanic(t *testing.T) {
+	app := New(1024)
+	app.Parallel = true
+
+	counter := &counterSystem{}
+	app.AddSystem(&accessSystem{})
+	app.AddSystem(&accessSystem{PanicAt: 10})
+	app.AddSystem(counter)
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+
+	err := app.RunE()
+	assert.ErrorContains(t, err, "updating system *app.accessSystem at tick 10: panic: access panic")
+	assert.True(t, counter.Finalized)
+}
+
+func TestSystemsParallelDisable(t *testing.T) {
+	app := New(1024)
+	app.Parallel = true
+
+	counter1 := &counterSystem{}
+	counter2 := &counterSystem{}
+	app.AddSystem(&accessSystem{Disable: counter1})
+	app.AddSystem(&accessSystem{Disable: counter2})
+	app.AddSystem(counter1, InStage(StagePostUpdate))
+	app.AddSystem(counter2, InStage(StagePostUpdate))
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+
+	app.Run()
+	assert.False(t, app.IsEnabled(counter1))
+	assert.False(t, app.IsEnabled(counter2))
+	assert.Equal(t, 1, counter1.Updates)
+}
+
+func TestSystemsPanicSequential(t *testing.T) {
+	app := New(1024)
+
+	counter := &counterSystem{}
+	app.AddSystem(&accessSystem{PanicAt: 10})
+	app.AddSystem(counter)
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+
+	err := app.RunE()
+	assert.ErrorContains(t, err, "updating system *app.accessSystem at tick 10: panic: access panic")
+	assert.True(t, counter.Finalized)
+}
+
+var errAccess = errors.New("access error")
+
+// accessSystem reads velocities, and optionally writes positions.
+type accessSystem struct {
+	Write   bool
+	FailAt  int64
+	PanicAt int64
+	Disable System
+	Updates int
+	filter  *ecs.Filter2[position, velocity]
+	tickRes ecs.Resource[resource.Tick]
+}
+
+func (s *accessSystem) Initialize(w *ecs.World) {
+	s.filter = ecs.NewFilter2[position, velocity](w)
+	s.tickRes = ecs.NewResource[resource.Tick](w)
+	if s.Write {
+		ecs.NewMap2[position, velocity](w).NewBatch(10, &position{}, &velocity{X: 1})
+	}
+}
+
+func (s *accessSystem) Access(w *ecs.World) Access {
+	access := Access{
+		Read:          []ecs.ID{ecs.ComponentID[velocity](w)},
+		ReadResources: []ecs.ResID{ecs.ResourceID[resource.Tick](w)},
+	}
+	if s.Write {
+		access.Write = []ecs.ID{ecs.ComponentID[position](w)}
+	} else {
+		access.Read = append(access.Read, ecs.ComponentID[position](w))
+	}
+	return access
+}
+
+func (s *accessSystem) UpdateE(w *ecs.World) error {
+	if s.FailAt > 0 && s.tickRes.Get().Tick == s.FailAt {
+		return errAccess
+	}
+	if s.PanicAt > 0 && s.tickRes.Get().Tick == s.PanicAt {
+		panic("access panic")
+	}
+	s.Update(w)
+	return nil
+}
+
+func (s *accessSystem) Update(w *ecs.World) {
+	s.Updates++
+	systems := ecs.GetResource[Systems](w)
+	if s.Disable != nil {
+		systems.Disable(s.Disable)
+	}
+	systems.Sync(func() {
+		query := s.filter.Query()
+		for query.Next() {
+			pos, vel := query.Get()
+			if s.Write {
+				pos.X += vel.X
+			}
+		}
+	})
+}
+
+func (s *accessSystem) Finalize(w *ecs.World) {}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Plugin bundles systems, resources and observers of a reusable model module,
+// like a movement or a disease module.
+//
+// Plugins are added to an [App] via [App.AddPlugin].
+// Each plugin type can be added only once per app.
+type Plugin interface {
+	Build(app *App) // Adds the plugin's systems, resources etc. to the app.
+}
+
+// PluginDependencies is an optional interface for a [Plugin] that depends on other plugins.
+//
+// Dependencies are built before the plugin itself.
+// Dependencies that are not added to the app yet are added automatically,
+// using the returned instances. Dependencies that are already added are not added again.
+type PluginDependencies interface {
+	Dependencies() []Plugin // Plugins this plugin depends on.
+}
+
+// AddPlugin adds plugins to the app, by calling their Build method.
+// Dependencies of plugins (see [PluginDependencies]) are added first.
+//
+// Panics if a plugin of the same type was already added,
+// or if plugin dependencies contain a cycle.
+func (app *App) AddPlugin(plugins ...Plugin) *App {
+	for _, p := range plugins {
+		if app.HasPlugin(p) {
+			panic(fmt.Sprintf("plugin %T is already added", p))
+		}
+		app.addPlugin(p, nil)
+	}
+	return app
+}
+
+// HasPlugin returns whether a plugin of the same type as the given one was added to the app.
+func (app *App) HasPlugin(plugin Plugin) bool {
+	tp := reflect.TypeOf(plugin)
+	for _, p := range app.plugins {
+		if reflect.TypeOf(p) == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// addPlugin adds a plugin after its dependencies.
+// The path contains the types of plugins currently being added, for cycle detection.
+func (app *App) addPlugin(plugin Plugin, path []reflect.Type) {
+	tp := reflect.TypeOf(plugin)
+	for i, other := range path {
+		if other == tp {
+			names := make([]string, 0, len(path)-i+1)
+			for _, t := range path[i:] {
+				names = append(names, t.String())
+			}
+			names = append(names, tp.String())
+			panic(fmt.Sprintf("cycle in plugin dependencies: %s", strings.Join(names, " -> ")))
+		}
+	}
+
+	if deps, ok := plugin.(PluginDependencies); ok {
+		path = append(path, tp)
+		for _, dep := range deps.Dependencies() {
+			if !app.HasPlugin(dep) {
+				app.addPlugin(dep, path)
+			}
+		}
+	}
+
+	plugin.Build(app)
+	app.plugins = append(app.plugins, plugin)
+}
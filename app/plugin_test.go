@@ -0,0 +1,92 @@
+package app_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type gridConfig struct {
+	Size int
+}
+
+// gridPlugin adds a grid configuration resource.
+type gridPlugin struct {
+	Size int
+}
+
+func (p *gridPlugin) Build(a *app.App) {
+	ecs.AddResource(&a.World, &gridConfig{Size: p.Size})
+}
+
+// movementPlugin depends on the grid plugin.
+type movementPlugin struct {
+	built *[]string
+}
+
+func (p *movementPlugin) Dependencies() []app.Plugin {
+	return []app.Plugin{&gridPlugin{Size: 10}}
+}
+
+func (p *movementPlugin) Build(a *app.App) {
+	*p.built = append(*p.built, "movement")
+	a.AddSystem(&system.FixedTermination{Steps: 10})
+}
+
+// cyclePluginA and cyclePluginB depend on each other.
+type cyclePluginA struct{}
+
+func (p *cyclePluginA) Dependencies() []app.Plugin { return []app.Plugin{&cyclePluginB{}} }
+func (p *cyclePluginA) Build(a *app.App)           {}
+
+type cyclePluginB struct{}
+
+func (p *cyclePluginB) Dependencies() []app.Plugin { return []app.Plugin{&cyclePluginA{}} }
+func (p *cyclePluginB) Build(a *app.App)           {}
+
+func TestAppAddPlugin(t *testing.T) {
+	built := []string{}
+	a := app.New(1024)
+	a.AddPlugin(&movementPlugin{built: &built})
+
+	assert.True(t, a.HasPlugin(&gridPlugin{}))
+	assert.True(t, a.HasPlugin(&movementPlugin{}))
+	assert.Equal(t, 10, ecs.GetResource[gridConfig](&a.World).Size)
+	assert.Equal(t, []string{"movement"}, built)
+
+	assert.PanicsWithValue(t, "plugin *app_test.gridPlugin is already added", func() {
+		a.AddPlugin(&gridPlugin{})
+	})
+
+	a.Reset()
+	assert.False(t, a.HasPlugin(&gridPlugin{}))
+
+	// An explicitly added dependency is not replaced.
+	a.AddPlugin(&gridPlugin{Size: 20}, &movementPlugin{built: &built})
+	assert.Equal(t, 20, ecs.GetResource[gridConfig](&a.World).Size)
+	a.Run()
+}
+
+func TestAppAddPluginCycle(t *testing.T) {
+	a := app.New(1024)
+	assert.PanicsWithValue(t,
+		"cycle in plugin dependencies: *app_test.cyclePluginA -> *app_test.cyclePluginB -> *app_test.cyclePluginA",
+		func() { a.AddPlugin(&cyclePluginA{}) },
+	)
+}
+
+func ExamplePlugin() {
+	myApp := app.New(1024)
+
+	// Adds the grid plugin as a dependency.
+	built := []string{}
+	myApp.AddPlugin(&movementPlugin{built: &built})
+
+	grid := ecs.GetResource[gridConfig](&myApp.World)
+	fmt.Println(grid.Size)
+	// Output: 10
+}
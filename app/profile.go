@@ -0,0 +1,212 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Profile is a resource holding timing statistics per system,
+// collected by the scheduler if [Systems.Profiling] is enabled.
+//
+// Measures the wall time of [System.Update] and [UISystem.UpdateUI] calls.
+// Statistics are calculated over a rolling window of the most recent calls.
+//
+// This resource is provided by [App] per default.
+// See [ProfileObserver] for extracting the statistics as an [observer.Row].
+type Profile struct {
+	Window  int // Number of most recent calls used for statistics. Default 100.
+	mutex   sync.Mutex
+	entries map[profileKey]*SystemProfile
+}
+
+// profileKey identifies the [SystemProfile] of a system.
+type profileKey struct {
+	System any
+	UI     bool
+}
+
+// Get returns the timing statistics of a system's updates,
+// and whether there are any.
+func (p *Profile) Get(sys System) (*SystemProfile, bool) {
+	return p.get(profileKey{System: sys})
+}
+
+// GetUI returns the timing statistics of a UI system's UI updates,
+// and whether there are any.
+func (p *Profile) GetUI(sys UISystem) (*SystemProfile, bool) {
+	return p.get(profileKey{System: sys, UI: true})
+}
+
+func (p *Profile) get(key profileKey) (*SystemProfile, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	entry, ok := p.entries[key]
+	return entry, ok
+}
+
+// record adds a measurement for a system.
+func (p *Profile) record(sys any, ui bool, dur time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.Window <= 0 {
+		p.Window = 100
+	}
+	if p.entries == nil {
+		p.entries = map[profileKey]*SystemProfile{}
+	}
+	key := profileKey{System: sys, UI: ui}
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &SystemProfile{}
+		p.entries[key] = entry
+	}
+	entry.add(dur, p.Window)
+}
+
+// Reset removes all statistics.
+func (p *Profile) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.entries = nil
+}
+
+// SystemProfile holds timing statistics of a single system.
+type SystemProfile struct {
+	Calls   int64         // Total number of measured calls.
+	Total   time.Duration // Total time of all measured calls.
+	samples []time.Duration
+	next    int
+}
+
+// add a measurement.
+func (p *SystemProfile) add(dur time.Duration, window int) {
+	p.Calls++
+	p.Total += dur
+	if len(p.samples) < window {
+		p.samples = append(p.samples, dur)
+		return
+	}
+	p.samples[p.next] = dur
+	p.next = (p.next + 1) % len(p.samples)
+}
+
+// Mean time over the rolling window.
+func (p *SystemProfile) Mean() time.Duration {
+	if len(p.samples) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, s := range p.samples {
+		sum += s
+	}
+	return sum / time.Duration(len(p.samples))
+}
+
+// Max time over the rolling window.
+func (p *SystemProfile) Max() time.Duration {
+	if len(p.samples) == 0 {
+		return 0
+	}
+	return slices.Max(p.samples)
+}
+
+// Percentile of the time over the rolling window, with q in the range [0, 100].
+// Uses the nearest-rank method.
+func (p *SystemProfile) Percentile(q float64) time.Duration {
+	if len(p.samples) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(p.samples)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(q / 100 * float64(len(sorted))))
+	return sorted[min(max(rank-1, 0), len(sorted)-1)]
+}
+
+// ProfileObserver is an [observer.Row] for the timing statistics in the [Profile] resource.
+//
+// Provides mean and maximum, as well as the given percentiles, for each system and UI system.
+// All values are in milliseconds.
+// Columns are determined from the systems present at initialization.
+type ProfileObserver struct {
+	Percentiles []float64 // Percentiles to report, in the range [0, 100].
+	systems     []System
+	uiSystems   []UISystem
+	header      []string
+	values      []float64
+	profileRes  ecs.Resource[Profile]
+}
+
+var _ observer.Row = &ProfileObserver{}
+
+// Initialize the observer.
+func (o *ProfileObserver) Initialize(w *ecs.World) {
+	systems := ecs.GetResource[Systems](w)
+	o.profileRes = ecs.NewResource[Profile](w)
+	o.systems = slices.Clone(systems.systems)
+	o.uiSystems = slices.Clone(systems.uiSystems)
+
+	o.header = o.header[:0]
+	for _, sys := range o.systems {
+		o.appendHeader(systems.systemName(sys))
+	}
+	for _, sys := range o.uiSystems {
+		o.appendHeader(systems.systemName(sys) + " UI")
+	}
+	o.values = make([]float64, len(o.header))
+}
+
+func (o *ProfileObserver) appendHeader(name string) {
+	o.header = append(o.header, name+" mean", name+" max")
+	for _, q := range o.Percentiles {
+		o.header = append(o.header, fmt.Sprintf("%s p%g", name, q))
+	}
+}
+
+// Update the observer.
+func (o *ProfileObserver) Update(w *ecs.World) {}
+
+// Header of the observer.
+func (o *ProfileObserver) Header() []string {
+	return o.header
+}
+
+// Values of the observer.
+func (o *ProfileObserver) Values(w *ecs.World) []float64 {
+	profile := o.profileRes.Get()
+	idx := 0
+	for _, sys := range o.systems {
+		entry, _ := profile.Get(sys)
+		idx = o.setValues(entry, idx)
+	}
+	for _, sys := range o.uiSystems {
+		entry, _ := profile.GetUI(sys)
+		idx = o.setValues(entry, idx)
+	}
+	return o.values
+}
+
+func (o *ProfileObserver) setValues(entry *SystemProfile, idx int) int {
+	if entry == nil {
+		entry = &SystemProfile{}
+	}
+	o.values[idx] = toMillis(entry.Mean())
+	o.values[idx+1] = toMillis(entry.Max())
+	idx += 2
+	for _, q := range o.Percentiles {
+		o.values[idx] = toMillis(entry.Percentile(q))
+		idx++
+	}
+	return idx
+}
+
+// toMillis converts a duration to fractional milliseconds.
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
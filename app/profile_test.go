@@ -0,0 +1,77 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemProfile(t *testing.T) {
+	p := SystemProfile{}
+	assert.Equal(t, time.Duration(0), p.Mean())
+	assert.Equal(t, time.Duration(0), p.Max())
+	assert.Equal(t, time.Duration(0), p.Percentile(50))
+
+	for i := 1; i <= 10; i++ {
+		p.add(time.Duration(i)*time.Millisecond, 5)
+	}
+
+	assert.Equal(t, int64(10), p.Calls)
+	assert.Equal(t, 55*time.Millisecond, p.Total)
+	assert.Equal(t, 8*time.Millisecond, p.Mean())
+	assert.Equal(t, 10*time.Millisecond, p.Max())
+	assert.Equal(t, 6*time.Millisecond, p.Percentile(0))
+	assert.Equal(t, 8*time.Millisecond, p.Percentile(50))
+	assert.Equal(t, 10*time.Millisecond, p.Percentile(95))
+}
+
+func TestProfile(t *testing.T) {
+	app := New(1024)
+	app.Profiling = true
+
+	sleep := sleepSystem{Tick: 5, Duration: 10 * time.Millisecond}
+	ui := uiSystem{}
+	counter := counterSystem{}
+	obs := ProfileObserver{Percentiles: []float64{50, 95}}
+
+	app.AddSystem(&sleep)
+	app.AddSystem(&counter)
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+	app.AddUISystem(&ui)
+
+	app.Initialize()
+	obs.Initialize(&app.World)
+	assert.Equal(t, []string{
+		"*app.sleepSystem mean", "*app.sleepSystem max", "*app.sleepSystem p50", "*app.sleepSystem p95",
+		"*app.counterSystem mean", "*app.counterSystem max", "*app.counterSystem p50", "*app.counterSystem p95",
+		"*system.FixedTermination mean", "*system.FixedTermination max", "*system.FixedTermination p50", "*system.FixedTermination p95",
+		"*app.uiSystem UI mean", "*app.uiSystem UI max", "*app.uiSystem UI p50", "*app.uiSystem UI p95",
+	}, obs.Header())
+
+	assert.Equal(t, make([]float64, 16), obs.Values(&app.World))
+
+	for app.Update() {
+		app.UpdateUI()
+	}
+	app.Finalize()
+
+	profile := ecs.GetResource[Profile](&app.World)
+	entry, ok := profile.Get(&sleep)
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), entry.Calls)
+	assert.GreaterOrEqual(t, entry.Max(), 10*time.Millisecond)
+
+	entry, ok = profile.GetUI(&ui)
+	assert.True(t, ok)
+	assert.Equal(t, int64(9), entry.Calls)
+
+	values := obs.Values(&app.World)
+	assert.GreaterOrEqual(t, values[1], 10.0)
+
+	profile.Reset()
+	_, ok = profile.Get(&sleep)
+	assert.False(t, ok)
+}
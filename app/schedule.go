@@ -0,0 +1,274 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Stage of an update step in which a [System] is updated.
+//
+// Systems are updated stage by stage, in the order of the stage constants.
+// Within a stage, systems are ordered by their ordering constraints (see [Before] and [After]),
+// their priority (see [WithPriority]) and the order in which they were added.
+type Stage uint8
+
+// Update stages, in the order of their execution.
+const (
+	StagePreUpdate  Stage = iota // Stage for preparing an update step.
+	StageUpdate                  // Stage for the model logic. The default.
+	StagePostUpdate              // Stage for processing the results of the model logic.
+	StageObserve                 // Stage for observing the model, e.g. by reporters and termination systems.
+)
+
+var stageNames = [...]string{"PreUpdate", "Update", "PostUpdate", "Observe"}
+
+// String returns the name of the stage.
+func (s Stage) String() string {
+	if int(s) < len(stageNames) {
+		return stageNames[s]
+	}
+	return fmt.Sprintf("Stage(%d)", s)
+}
+
+// Option configures the scheduling of a [System].
+// Options are passed to [Systems.AddSystem] and [Systems.AddUISystem].
+type Option func(*systemInfo)
+
+// InStage sets the [Stage] a system is updated in.
+// The default is [StageUpdate].
+func InStage(stage Stage) Option {
+	return func(info *systemInfo) {
+		info.stage = stage
+	}
+}
+
+// WithPriority sets the priority of a system within its [Stage].
+// Systems with a higher priority are updated first. The default is 0.
+//
+// Priorities are subordinate to the constraints set by [Before] and [After].
+func WithPriority(priority int) Option {
+	return func(info *systemInfo) {
+		info.priority = priority
+	}
+}
+
+// Before requires a system to be updated before the given systems.
+//
+// Systems that are not in the app are ignored.
+// Constraints between systems in different stages must be consistent with the stage order.
+func Before(systems ...System) Option {
+	return func(info *systemInfo) {
+		info.before = append(info.before, systems...)
+	}
+}
+
+// After requires a system to be updated after the given systems.
+//
+// Systems that are not in the app are ignored.
+// Constraints between systems in different stages must be consistent with the stage order.
+func After(systems ...System) Option {
+	return func(info *systemInfo) {
+		info.after = append(info.after, systems...)
+	}
+}
+
+// WithInterval updates a system only every interval ticks, starting at the given offset.
+// I.e., the system is updated in ticks offset, offset+interval, offset+2*interval, ...
+//
+// Intervals are based on [resource.Tick]. The default is an interval of 1 and an offset of 0,
+// i.e. updates in every tick. Panics if the interval is not positive or the offset is negative.
+func WithInterval(interval, offset int64) Option {
+	if interval <= 0 {
+		panic(fmt.Sprintf("update interval must be positive, got %d", interval))
+	}
+	if offset < 0 {
+		panic(fmt.Sprintf("update offset must not be negative, got %d", offset))
+	}
+	return func(info *systemInfo) {
+		info.interval = interval
+		info.offset = offset
+	}
+}
+
+// WithStart updates a system only from the given tick on.
+// It is still initialized at the start of the run.
+func WithStart(tick int64) Option {
+	return func(info *systemInfo) {
+		info.start = tick
+	}
+}
+
+// WithEnd updates a system only before the given tick, i.e. the system is not updated in this tick.
+// It is still finalized at the end of the run.
+func WithEnd(tick int64) Option {
+	return func(info *systemInfo) {
+		info.end = tick
+	}
+}
+
+// WithName sets a name for a system or UI system.
+// Names must be unique within an app.
+//
+// Names are used for looking up systems via [Systems.Find], as well as in error messages and reports.
+// Systems without a name are referred to by their type.
+func WithName(name string) Option {
+	return func(info *systemInfo) {
+		info.name = name
+	}
+}
+
+// systemInfo holds scheduling information for a [System] or [UISystem].
+type systemInfo struct {
+	name     string
+	enabled  bool
+	stage    Stage
+	priority int
+	before   []System
+	after    []System
+	interval int64
+	offset   int64
+	start    int64
+	end      int64
+	updater  UpdaterE // The system as UpdaterE, if it implements it. Cached to avoid type assertions during updates.
+	always   bool     // Whether the system is enabled and updated in every tick. Cached for fast checks during updates.
+}
+
+// newSystemInfo creates scheduling information from options.
+func newSystemInfo(options []Option) *systemInfo {
+	info := systemInfo{
+		enabled:  true,
+		stage:    StageUpdate,
+		interval: 1,
+		end:      math.MaxInt64,
+	}
+	for _, opt := range options {
+		opt(&info)
+	}
+	return &info
+}
+
+// isDue returns whether a system is to be updated in the given tick.
+func (info *systemInfo) isDue(tick int64) bool {
+	if !info.enabled {
+		return false
+	}
+	if tick < info.start || tick >= info.end || tick < info.offset {
+		return false
+	}
+	return info.interval == 1 || (tick-info.offset)%info.interval == 0
+}
+
+// sortSystems sorts the systems topologically, by stage, ordering constraints and priority.
+// Returns an error if the constraints contain a cycle or contradict the stage order.
+func (s *Systems) sortSystems() error {
+	n := len(s.systems)
+	infos := make([]*systemInfo, n)
+	indices := make(map[System][]int, n)
+	for i, sys := range s.systems {
+		infos[i] = s.systemInfo(sys)
+		indices[sys] = append(indices[sys], i)
+	}
+
+	successors := make([][]int, n)
+	predecessors := make([]int, n)
+	addEdge := func(from, to int) error {
+		if infos[from].stage > infos[to].stage {
+			return fmt.Errorf("system %s in stage %s can't be updated before system %s in stage %s",
+				s.systemName(s.systems[from]), infos[from].stage, s.systemName(s.systems[to]), infos[to].stage)
+		}
+		successors[from] = append(successors[from], to)
+		predecessors[to]++
+		return nil
+	}
+
+	for i, info := range infos {
+		for _, other := range info.before {
+			for _, j := range indices[other] {
+				if err := addEdge(i, j); err != nil {
+					return err
+				}
+			}
+		}
+		for _, other := range info.after {
+			for _, j := range indices[other] {
+				if err := addEdge(j, i); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	less := func(a, b int) bool {
+		if infos[a].stage != infos[b].stage {
+			return infos[a].stage < infos[b].stage
+		}
+		if infos[a].priority != infos[b].priority {
+			return infos[a].priority > infos[b].priority
+		}
+		return a < b
+	}
+
+	sorted := make([]System, 0, n)
+	done := make([]bool, n)
+	for len(sorted) < n {
+		next := -1
+		for i := range n {
+			if done[i] || predecessors[i] > 0 {
+				continue
+			}
+			if next < 0 || less(i, next) {
+				next = i
+			}
+		}
+		if next < 0 {
+			cycle := []string{}
+			for i := range n {
+				if !done[i] {
+					cycle = append(cycle, s.systemName(s.systems[i]))
+				}
+			}
+			return fmt.Errorf("cycle in the ordering constraints of systems %s", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		sorted = append(sorted, s.systems[next])
+		for _, j := range successors[next] {
+			predecessors[j]--
+		}
+	}
+
+	s.systems = sorted
+	s.updateInfos()
+	s.planned = false
+	return nil
+}
+
+// updateInfos collects copies of the scheduling information of systems and UI systems, in their update order.
+// Copies are kept contiguous in memory, for fast access during updates.
+// Must be called whenever systems are added, removed, re-ordered, enabled or disabled.
+func (s *Systems) updateInfos() {
+	s.infos = s.infos[:0]
+	for _, sys := range s.systems {
+		info := *s.systemInfo(sys)
+		info.updater, _ = sys.(UpdaterE)
+		info.always = info.enabled && info.interval == 1 && info.offset <= 0 && info.start <= 0 && info.end == math.MaxInt64
+		s.infos = append(s.infos, info)
+	}
+	s.uiInfos = s.uiInfos[:0]
+	for _, sys := range s.uiSystems {
+		s.uiInfos = append(s.uiInfos, *s.systemInfo(sys))
+	}
+}
+
+// defaultSystemInfo is the scheduling information of systems added without options.
+var defaultSystemInfo = newSystemInfo(nil)
+
+// systemInfo returns the scheduling information for a system or UI system.
+func (s *Systems) systemInfo(sys any) *systemInfo {
+	s.changeMutex.Lock()
+	defer s.changeMutex.Unlock()
+	if info, ok := s.info[sys]; ok {
+		return info
+	}
+	return defaultSystemInfo
+}
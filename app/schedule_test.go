@@ -0,0 +1,102 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStage(t *testing.T) {
+	assert.Equal(t, "PreUpdate", StagePreUpdate.String())
+	assert.Equal(t, "Observe", StageObserve.String())
+	assert.Equal(t, "Stage(10)", Stage(10).String())
+}
+
+func TestSystemsOrder(t *testing.T) {
+	app := New(1024)
+
+	a := &counterSystem{}
+	b := &counterSystem{}
+	c := &counterSystem{}
+	d := &counterSystem{}
+	e := &counterSystem{}
+	ui := &dualCounterSystem{}
+
+	app.AddSystem(a, InStage(StageObserve))
+	app.AddSystem(b)
+	app.AddSystem(c, After(d))
+	app.AddSystem(d, WithPriority(-1))
+	app.AddSystem(e, InStage(StagePreUpdate), Before(a, b))
+	app.AddUISystem(ui, WithPriority(1))
+
+	app.Initialize()
+
+	assert.Equal(t, []System{e, ui, b, d, c, a}, app.systems)
+}
+
+func TestSystemsOrderError(t *testing.T) {
+	app := New(1024)
+
+	a := &counterSystem{}
+	b := &counterSystem{}
+	c := &counterSystem{}
+
+	app.AddSystem(a, After(c), WithName("a"))
+	app.AddSystem(b, After(a), WithName("b"))
+	app.AddSystem(c, After(b))
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+
+	err := app.RunE()
+	assert.ErrorContains(t, err, "cycle in the ordering constraints of systems a, b, *app.counterSystem")
+	assert.False(t, a.Finalized)
+
+	app = New(1024)
+	app.AddSystem(a, InStage(StagePostUpdate), WithName("a"))
+	app.AddSystem(b, Before(a), InStage(StageObserve), WithName("b"))
+
+	err = app.RunE()
+	assert.ErrorContains(t, err, "system b in stage Observe can't be updated before system a in stage PostUpdate")
+}
+
+func TestSystemsOrderAddRunning(t *testing.T) {
+	app := New(1024)
+
+	a := &counterSystem{}
+	b := &counterSystem{}
+
+	app.AddSystem(a)
+	app.AddSystem(&system.FixedTermination{Steps: 10}, InStage(StageObserve))
+	app.Initialize()
+
+	app.AddSystem(b, Before(a))
+	assert.Equal(t, b, app.systems[0])
+
+	app.RemoveSystem(a)
+	app.Update()
+	assert.Equal(t, 2, len(app.systems))
+	assert.Equal(t, 2, len(app.info))
+}
+
+func TestSystemsInterval(t *testing.T) {
+	app := New(1024)
+
+	a := &counterSystem{}
+	b := &counterSystem{}
+	c := &counterSystem{}
+
+	app.AddSystem(a, WithInterval(7, 0))
+	app.AddSystem(b, WithInterval(10, 3), WithStart(20), WithEnd(60))
+	app.AddSystem(c, WithEnd(0))
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+
+	app.Run()
+
+	assert.Equal(t, 15, a.Updates)
+	assert.Equal(t, 4, b.Updates)
+	assert.Equal(t, 0, c.Updates)
+	assert.True(t, c.Finalized)
+
+	assert.Panics(t, func() { WithInterval(0, 0) })
+	assert.Panics(t, func() { WithInterval(1, -1) })
+}
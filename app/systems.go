@@ -1,7 +1,10 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/mlange-42/ark-tools/resource"
@@ -27,6 +30,29 @@ type UISystem interface {
 	FinalizeUI(w *ecs.World)   // FinalizeUI the system.
 }
 
+// InitializerE is an optional, error-aware variant of [System.Initialize].
+//
+// If a [System] implements it, InitializeE is called instead of Initialize.
+// An error aborts the run started with [App.RunE].
+type InitializerE interface {
+	InitializeE(w *ecs.World) error // Initialize the system.
+}
+
+// UpdaterE is an optional, error-aware variant of [System.Update].
+//
+// If a [System] implements it, UpdateE is called instead of Update.
+// An error aborts the run started with [App.RunE].
+type UpdaterE interface {
+	UpdateE(w *ecs.World) error // Update the system.
+}
+
+// FinalizerE is an optional, error-aware variant of [System.Finalize].
+//
+// If a [System] implements it, FinalizeE is called instead of Finalize.
+type FinalizerE interface {
+	FinalizeE(w *ecs.World) error // Finalize the system.
+}
+
 // Systems manages and schedules ECS [System] and [UISystem] instances.
 //
 // [System] instances are updated with a frequency given by TPS (ticks per second).
@@ -42,24 +68,58 @@ type Systems struct {
 	// A zero/unset value defaults to 30 FPS. Values < 0 sync FPS with TPS.
 	// With fast movement, a value of 60 may be required for fluent graphics.
 	FPS float64
+	// Maximum number of ticks per frame to catch up with TPS when the simulation falls behind.
+	// Values > 0 enable a fixed time step with an accumulator: elapsed time is accumulated,
+	// and consumed in ticks of 1/TPS, up to MaxCatchUp ticks per frame. Time beyond that is dropped.
+	// Values <= 0 (the default) skip ahead when the simulation falls more than 200ms behind.
+	// Only has an effect with TPS > 0.
+	MaxCatchUp int
+	// Whether the wall time of system updates is measured.
+	// Statistics are collected in the [Profile] resource.
+	Profiling bool
 	// Whether the simulation is currently paused.
 	// When paused, only UI updates but no normal updates are performed.
+	// See [Systems.Step] for performing single ticks while paused.
 	Paused bool
+	// Whether independent systems of the same [Stage] are updated concurrently.
+	// See [AccessSystem] for how systems declare their independence.
+	// When false (the default), systems are updated sequentially, for bitwise reproducibility.
+	Parallel bool
 
 	world      *ecs.World
 	systems    []System
 	uiSystems  []UISystem
 	toRemove   []System
 	uiToRemove []UISystem
-
-	nextDraw   time.Time
-	nextUpdate time.Time
+	toAdd      []System
+	uiToAdd    []UISystem
+	info       map[any]*systemInfo
+	infos      []systemInfo // Scheduling information in the order of systems, to avoid lookups during updates.
+	uiInfos    []systemInfo // Scheduling information in the order of UI systems.
+	toToggle   []toggle
+	batches    [][]int // Batches of indices of systems that can be updated concurrently.
+	planned    bool
+	mutex      sync.Mutex
+	// Guards the deferred changes and the system info,
+	// as systems may add, remove, enable or disable systems during concurrent updates.
+	changeMutex sync.Mutex
+
+	steps int
+
+	nextDraw    time.Time
+	nextUpdate  time.Time
+	lastUpdate  time.Time
+	accumulator time.Duration
 
 	initialized bool
+	finalized   bool
 	locked      bool
 
-	tickRes ecs.Resource[resource.Tick]
-	termRes ecs.Resource[resource.Termination]
+	tickRes   ecs.Resource[resource.Tick]
+	timeRes   ecs.Resource[resource.ModelTime]
+	termRes   ecs.Resource[resource.Termination]
+	interpRes ecs.Resource[resource.Interpolation]
+	profRes   ecs.Resource[Profile]
 }
 
 // Systems returns the normal/non-UI systems.
@@ -74,24 +134,61 @@ func (s *Systems) UISystems() []UISystem {
 
 // AddSystem adds a [System] to the app.
 //
-// Panics if the system is also a [UISystem].
+// Systems can also be added after app initialization, e.g. during a run.
+// In this case, the system is initialized at the end of the current update step,
+// and it is updated from the next tick on.
+//
+// Options control the scheduling of the system, see [Option].
+// Without options, systems are updated in the order they were added.
+//
+// Panics if the system is also a [UISystem], or if the app is already finalized.
 // To add systems that implement both [System] and [UISystem], use [Systems.AddUISystem]
-func (s *Systems) AddSystem(sys System) {
-	if s.initialized {
-		panic("adding systems after app initialization is not implemented yet")
-	}
+func (s *Systems) AddSystem(sys System, options ...Option) {
 	if sys, ok := sys.(UISystem); ok {
 		panic(fmt.Sprintf("System %T is also an UI system. Must be added via AddUISystem.", sys))
 	}
+	if s.finalized {
+		panic(fmt.Sprintf("can't add system %T: the app is already finalized", sys))
+	}
+	s.setInfo(sys, options)
+	if s.initialized || s.locked {
+		s.changeMutex.Lock()
+		s.toAdd = append(s.toAdd, sys)
+		s.changeMutex.Unlock()
+		if !s.locked {
+			if err := s.addSystems(); err != nil {
+				panic(err)
+			}
+		}
+		return
+	}
 	s.systems = append(s.systems, sys)
 }
 
 // AddUISystem adds an [UISystem] to the app.
 //
 // Adds the [UISystem] also as a normal [System] if it implements the interface.
-func (s *Systems) AddUISystem(sys UISystem) {
-	if s.initialized {
-		panic("adding systems after app initialization is not implemented yet")
+//
+// Like with [Systems.AddSystem], UI systems can also be added after app initialization.
+// Options related to ordering and intervals only affect the normal [System] part.
+// UI systems are updated in the order they were added.
+//
+// Panics if the app is already finalized.
+func (s *Systems) AddUISystem(sys UISystem, options ...Option) {
+	if s.finalized {
+		panic(fmt.Sprintf("can't add system %T: the app is already finalized", sys))
+	}
+	s.setInfo(sys, options)
+	if s.initialized || s.locked {
+		s.changeMutex.Lock()
+		s.uiToAdd = append(s.uiToAdd, sys)
+		s.changeMutex.Unlock()
+		if !s.locked {
+			if err := s.addSystems(); err != nil {
+				panic(err)
+			}
+		}
+		return
 	}
 	s.uiSystems = append(s.uiSystems, sys)
 	if sys, ok := sys.(System); ok {
@@ -103,13 +200,18 @@ func (s *Systems) AddUISystem(sys UISystem) {
 //
 // Systems can also be removed during a run.
 // However, this will take effect only after the end of the full update step.
+// Systems are finalized on removal, unless they are removed before app initialization.
 func (s *Systems) RemoveSystem(sys System) {
 	if sys, ok := sys.(UISystem); ok {
 		panic(fmt.Sprintf("System %T is also an UI system. Must be removed via RemoveUISystem.", sys))
 	}
+	s.changeMutex.Lock()
 	s.toRemove = append(s.toRemove, sys)
+	s.changeMutex.Unlock()
 	if !s.locked {
-		s.removeSystems()
+		if err := s.removeSystems(); err != nil {
+			panic(err)
+		}
 	}
 }
 
@@ -117,15 +219,20 @@ func (s *Systems) RemoveSystem(sys System) {
 //
 // Systems can also be removed during a run.
 // However, this will take effect only after the end of the full update step.
+// Systems are finalized on removal, unless they are removed before app initialization.
 func (s *Systems) RemoveUISystem(sys UISystem) {
+	s.changeMutex.Lock()
 	s.uiToRemove = append(s.uiToRemove, sys)
+	s.changeMutex.Unlock()
 	if !s.locked {
-		s.removeSystems()
+		if err := s.removeSystems(); err != nil {
+			panic(err)
+		}
 	}
 }
 
 // Removes systems that were removed during the update step.
-func (s *Systems) removeSystems() {
+func (s *Systems) removeSystems() error {
 	rem := s.toRemove
 	remUI := s.uiToRemove
 
@@ -133,17 +240,94 @@ func (s *Systems) removeSystems() {
 	s.uiToRemove = s.uiToRemove[:0]
 
 	for _, sys := range rem {
-		s.removeSystem(sys)
+		if err := s.removeSystem(sys); err != nil {
+			return err
+		}
 	}
 	for _, sys := range remUI {
 		if sys, ok := sys.(System); ok {
-			s.removeSystem(sys)
+			if err := s.removeSystem(sys); err != nil {
+				return err
+			}
 		}
-		s.removeUISystem(sys)
+		if err := s.removeUISystem(sys); err != nil {
+			return err
+		}
+	}
+	if len(rem) > 0 || len(remUI) > 0 {
+		s.updateInfos()
 	}
+	return nil
 }
 
-func (s *Systems) removeSystem(sys System) {
+// Stores scheduling information for a system.
+func (s *Systems) setInfo(sys any, options []Option) {
+	s.changeMutex.Lock()
+	defer s.changeMutex.Unlock()
+
+	if s.info == nil {
+		s.info = map[any]*systemInfo{}
+	}
+	info := newSystemInfo(options)
+	if info.name != "" {
+		if other, ok := s.find(info.name); ok && other != sys {
+			panic(fmt.Sprintf("can't add system %T: name '%s' is already used by system %T", sys, info.name, other))
+		}
+	}
+	s.info[sys] = info
+}
+
+// Initializes and adds systems that were added after app initialization.
+func (s *Systems) addSystems() error {
+	if len(s.toAdd) == 0 && len(s.uiToAdd) == 0 {
+		return nil
+	}
+
+	s.locked = true
+	defer func() { s.locked = false }()
+
+	for len(s.toAdd) > 0 || len(s.uiToAdd) > 0 {
+		add := s.toAdd
+		addUI := s.uiToAdd
+
+		s.toAdd = nil
+		s.uiToAdd = nil
+
+		for _, sys := range add {
+			if err := s.initializeSystem(sys); err != nil {
+				return err
+			}
+			s.systems = append(s.systems, sys)
+		}
+		for _, sys := range addUI {
+			if sys, ok := sys.(System); ok {
+				if err := s.initializeSystem(sys); err != nil {
+					return err
+				}
+				s.systems = append(s.systems, sys)
+			}
+			sys.InitializeUI(s.world)
+			s.uiSystems = append(s.uiSystems, sys)
+		}
+	}
+	return s.sortSystems()
+}
+
+// Applies deferred removal, addition, enabling and disabling of systems.
+func (s *Systems) applyChanges() error {
+	if len(s.toRemove) == 0 && len(s.uiToRemove) == 0 && len(s.toAdd) == 0 && len(s.uiToAdd) == 0 && len(s.toToggle) == 0 {
+		return nil
+	}
+	if err := s.removeSystems(); err != nil {
+		return err
+	}
+	if err := s.addSystems(); err != nil {
+		return err
+	}
+	return s.toggleSystems()
+}
+
+func (s *Systems) removeSystem(sys System) error {
 	if s.locked {
 		panic("can't remove a system in locked state")
 	}
@@ -155,13 +339,22 @@ func (s *Systems) removeSystem(sys System) {
 		}
 	}
 	if idx < 0 {
-		panic(fmt.Sprintf("can't remove system %T: not in the app", sys))
+		return fmt.Errorf("can't remove system %T: not in the app", sys)
+	}
+	// Systems removed before app initialization were never initialized, so they are not finalized.
+	var err error
+	if s.initialized {
+		err = s.finalizeSystem(s.systems[idx])
 	}
-	s.systems[idx].Finalize(s.world)
 	s.systems = append(s.systems[:idx], s.systems[idx+1:]...)
+	s.changeMutex.Lock()
+	delete(s.info, sys)
+	s.changeMutex.Unlock()
+	s.planned = false
+	return err
 }
 
-func (s *Systems) removeUISystem(sys UISystem) {
+func (s *Systems) removeUISystem(sys UISystem) error {
 	if s.locked {
 		panic("can't remove a system in locked state")
 	}
@@ -173,14 +366,231 @@ func (s *Systems) removeUISystem(sys UISystem) {
 		}
 	}
 	if idx < 0 {
-		panic(fmt.Sprintf("can't remove UI system %T: not in the app", sys))
+		return fmt.Errorf("can't remove UI system %T: not in the app", sys)
+	}
+	if s.initialized {
+		s.uiSystems[idx].FinalizeUI(s.world)
 	}
-	s.uiSystems[idx].FinalizeUI(s.world)
 	s.uiSystems = append(s.uiSystems[:idx], s.uiSystems[idx+1:]...)
+	s.changeMutex.Lock()
+	delete(s.info, sys)
+	s.changeMutex.Unlock()
+	return nil
+}
+
+// Step performs the given number of simulation ticks while paused, and stays paused afterwards.
+// Pauses the simulation if it is not paused yet.
+//
+// Ticks are performed with the normal tick rate (TPS),
+// while UI systems are updated at the limited frame rate of the paused state.
+// Calls accumulate, i.e. calling Step(1) twice performs two ticks.
+//
+// Panics if the number of steps is negative.
+func (s *Systems) Step(n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("number of steps must not be negative, got %d", n))
+	}
+	s.Paused = true
+	s.steps += n
+}
+
+// Find returns the system or UI system with the given name, and whether it was found.
+// Names are assigned with option [WithName] when adding systems.
+//
+// Also finds systems that were added after app initialization, but are not initialized yet.
+// Never finds anything for an empty name, as systems without a name can't be distinguished.
+func (s *Systems) Find(name string) (any, bool) {
+	s.changeMutex.Lock()
+	defer s.changeMutex.Unlock()
+	return s.find(name)
+}
+
+// find is like [Systems.Find], but requires the caller to hold the change mutex.
+func (s *Systems) find(name string) (any, bool) {
+	if name == "" {
+		return nil, false
+	}
+	for sys, info := range s.info {
+		if info.name == name {
+			return sys, true
+		}
+	}
+	return nil, false
+}
+
+// FindSystem returns the first system or UI system of type T, and whether it was found.
+// T can also be an interface type.
+//
+// Only finds systems that are initialized, or that were added before app initialization.
+func FindSystem[T any](s *Systems) (T, bool) {
+	for _, sys := range s.systems {
+		if sys, ok := sys.(T); ok {
+			return sys, true
+		}
+	}
+	for _, sys := range s.uiSystems {
+		if sys, ok := sys.(T); ok {
+			return sys, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Enable a system or UI system that was disabled by [Systems.Disable].
+//
+// Systems can also be enabled during a run.
+// However, this will take effect only after the end of the full update step.
+func (s *Systems) Enable(sys any) {
+	s.setEnabled(sys, true)
+}
+
+// Disable a system or UI system.
+// Disabled systems are not updated, but they are not finalized until the end of the run.
+// See [Systems.Enable] for enabling them again.
+//
+// Systems can also be disabled during a run.
+// However, this will take effect only after the end of the full update step.
+func (s *Systems) Disable(sys any) {
+	s.setEnabled(sys, false)
+}
+
+// IsEnabled returns whether a system or UI system is enabled.
+//
+// Panics if the system is not in the app.
+func (s *Systems) IsEnabled(sys any) bool {
+	s.changeMutex.Lock()
+	info, ok := s.info[sys]
+	s.changeMutex.Unlock()
+	if !ok {
+		panic(fmt.Sprintf("system %T is not in the app", sys))
+	}
+	return info.enabled
+}
+
+// toggle is a deferred enabling or disabling of a system.
+type toggle struct {
+	System  any
+	Enabled bool
+}
+
+func (s *Systems) setEnabled(sys any, enabled bool) {
+	s.changeMutex.Lock()
+	s.toToggle = append(s.toToggle, toggle{System: sys, Enabled: enabled})
+	s.changeMutex.Unlock()
+	if !s.locked {
+		if err := s.toggleSystems(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Enables and disables systems that were enabled or disabled during the update step.
+func (s *Systems) toggleSystems() error {
+	toggles := s.toToggle
+	s.toToggle = nil
+	for _, t := range toggles {
+		info, ok := s.info[t.System]
+		if !ok {
+			return fmt.Errorf("can't enable or disable system %T: not in the app", t.System)
+		}
+		info.enabled = t.Enabled
+	}
+	if len(toggles) > 0 {
+		s.updateInfos()
+	}
+	return nil
+}
+
+// Initializes a single system, using [InitializerE] if implemented.
+func (s *Systems) initializeSystem(sys System) error {
+	if sys, ok := sys.(InitializerE); ok {
+		if err := sys.InitializeE(s.world); err != nil {
+			return s.systemError("initializing", sys, err)
+		}
+		return nil
+	}
+	sys.Initialize(s.world)
+	return nil
+}
+
+// Updates a single system, using [UpdaterE] if implemented.
+// Skips systems that are not due in the current tick.
+func (s *Systems) updateSystem(sys System, info *systemInfo, tick int64) error {
+	if !info.isDue(tick) {
+		return nil
+	}
+	if s.Profiling {
+		start := time.Now()
+		err := s.callUpdate(sys, info)
+		s.profRes.Get().record(sys, false, time.Since(start))
+		return err
+	}
+	return s.callUpdate(sys, info)
+}
+
+// Calls the update method of a system.
+func (s *Systems) callUpdate(sys System, info *systemInfo) error {
+	if info.updater != nil {
+		if err := info.updater.UpdateE(s.world); err != nil {
+			return s.systemError("updating", sys, err)
+		}
+		return nil
+	}
+	sys.Update(s.world)
+	return nil
+}
+
+// Converts a panic during the update of a system into an error of that system.
+// Must be deferred directly.
+func (s *Systems) recoverUpdate(sys *System, err *error) {
+	if r := recover(); r != nil {
+		*err = s.systemError("updating", *sys, fmt.Errorf("panic: %v", r))
+	}
+}
+
+// Finalizes a single system, using [FinalizerE] if implemented.
+func (s *Systems) finalizeSystem(sys System) error {
+	if sys, ok := sys.(FinalizerE); ok {
+		if err := sys.FinalizeE(s.world); err != nil {
+			return s.systemError("finalizing", sys, err)
+		}
+		return nil
+	}
+	sys.Finalize(s.world)
+	return nil
+}
+
+// Returns the name of a system, for use in messages and reports.
+// Falls back to the system's type if it has no name.
+func (s *Systems) systemName(sys any) string {
+	s.changeMutex.Lock()
+	defer s.changeMutex.Unlock()
+	if info, ok := s.info[sys]; ok && info.name != "" {
+		return info.name
+	}
+	return fmt.Sprintf("%T", sys)
+}
+
+// Wraps an error returned by a system, naming the system and the current tick.
+func (s *Systems) systemError(action string, sys any, err error) error {
+	var tick int64
+	if s.tickRes != (ecs.Resource[resource.Tick]{}) {
+		tick = s.tickRes.Get().Tick
+	}
+	return fmt.Errorf("%s system %s at tick %d: %w", action, s.systemName(sys), tick, err)
 }
 
 // Initialize all systems.
 func (s *Systems) initialize() {
+	if err := s.initializeE(); err != nil {
+		panic(err)
+	}
+}
+
+// Initialize all systems, and return the first error.
+// On error, all systems that were already initialized are finalized.
+func (s *Systems) initializeE() error {
 	if s.initialized {
 		panic("app is already initialized")
 	}
@@ -190,42 +600,124 @@ func (s *Systems) initialize() {
 	}
 
 	s.tickRes = ecs.NewResource[resource.Tick](s.world)
+	s.timeRes = ecs.NewResource[resource.ModelTime](s.world)
 	s.termRes = ecs.NewResource[resource.Termination](s.world)
+	s.interpRes = ecs.NewResource[resource.Interpolation](s.world)
+	s.profRes = ecs.NewResource[Profile](s.world)
+
+	if err := s.sortSystems(); err != nil {
+		return err
+	}
 
 	s.locked = true
-	for _, sys := range s.systems {
-		sys.Initialize(s.world)
+	for i, sys := range s.systems {
+		if err := s.initializeSystem(sys); err != nil {
+			for _, done := range s.systems[:i] {
+				err = errors.Join(err, s.finalizeSystem(done))
+			}
+			s.locked = false
+			s.finalized = true
+			return err
+		}
 	}
 	for _, sys := range s.uiSystems {
 		sys.InitializeUI(s.world)
 	}
 	s.locked = false
-	s.removeSystems()
+	// Set before applying changes, so that systems removed during initialization are finalized.
 	s.initialized = true
+	if err := s.applyChanges(); err != nil {
+		return errors.Join(err, s.finalizeE())
+	}
 
 	s.nextDraw = time.Time{}
 	s.nextUpdate = time.Time{}
+	s.lastUpdate = time.Time{}
 
 	s.tickRes.Get().Tick = 0
+	if s.timeRes.Has() {
+		modelTime := s.timeRes.Get()
+		modelTime.Time = modelTime.Start
+	}
+	return nil
 }
 
 // Update all systems.
-func (s *Systems) update() bool {
+func (s *Systems) update(ctx context.Context) (bool, error) {
+	if s.MaxCatchUp > 0 && s.TPS > 0 && !s.Paused {
+		return s.updateAccumulated(ctx)
+	}
+	s.lastUpdate = time.Time{}
+
 	s.locked = true
-	update := s.updateSystemsTimed()
-	s.updateUISystemsTimed(update)
+	update, err := s.updateSystemsTimed()
+	if err == nil {
+		s.updateUISystemsTimed(update)
+	}
 	s.locked = false
+	if err != nil {
+		return false, err
+	}
 
-	s.removeSystems()
+	if err := s.applyChanges(); err != nil {
+		return false, err
+	}
 
 	if update {
-		time := s.tickRes.Get()
-		time.Tick++
+		s.advanceTick()
 	} else {
-		s.wait()
+		s.wait(ctx)
 	}
 
-	return !s.termRes.Get().Terminate
+	return !s.termRes.Get().Terminate, nil
+}
+
+// Update all systems, with a fixed time step and an accumulator for catching up.
+func (s *Systems) updateAccumulated(ctx context.Context) (bool, error) {
+	dt := time.Duration(float64(time.Second) / s.TPS)
+	now := time.Now()
+	if s.lastUpdate.IsZero() {
+		s.accumulator = dt
+	} else {
+		s.accumulator += now.Sub(s.lastUpdate)
+	}
+	s.lastUpdate = now
+
+	updated := false
+	for ticks := 0; s.accumulator >= dt; ticks++ {
+		if ticks >= s.MaxCatchUp {
+			s.accumulator %= dt
+			break
+		}
+		s.locked = true
+		err := s.updateSystemsSimple()
+		s.locked = false
+		if err != nil {
+			return false, err
+		}
+		if err := s.applyChanges(); err != nil {
+			return false, err
+		}
+		s.advanceTick()
+		s.accumulator -= dt
+		updated = true
+
+		if s.termRes.Get().Terminate {
+			return false, nil
+		}
+	}
+	s.nextUpdate = now.Add(dt - s.accumulator)
+
+	s.locked = true
+	s.updateUISystemsTimed(updated)
+	s.locked = false
+	if err := s.applyChanges(); err != nil {
+		return false, err
+	}
+
+	s.wait(ctx)
+
+	return !s.termRes.Get().Terminate, nil
 }
 
 // updateSystems updates all normal systems
@@ -234,36 +726,53 @@ func (s *Systems) updateSystems() bool {
 		panic("the app is not initialized")
 	}
 	if s.Paused {
-		return true
+		if s.steps <= 0 {
+			return true
+		}
+		s.steps--
 	}
 	s.locked = true
-	updated := s.updateSystemsSimple()
+	err := s.updateSystemsSimple()
 	s.locked = false
+	if err != nil {
+		panic(err)
+	}
 
-	s.removeSystems()
-
-	if updated {
-		time := s.tickRes.Get()
-		time.Tick++
+	if err := s.applyChanges(); err != nil {
+		panic(err)
 	}
 
+	s.advanceTick()
+
 	return !s.termRes.Get().Terminate
 }
 
+// advanceTick increments the tick, and advances the model time.
+func (s *Systems) advanceTick() {
+	s.tickRes.Get().Tick++
+	if s.timeRes.Has() {
+		s.timeRes.Get().Advance()
+	}
+}
+
 // updateUISystems updates all UI systems
 func (s *Systems) updateUISystems() {
 	if !s.initialized {
 		panic("the app is not initialized")
 	}
+	s.updateInterpolation()
 	s.locked = true
 	s.updateUISystemsSimple()
 	s.locked = false
 
-	s.removeSystems()
+	if err := s.applyChanges(); err != nil {
+		panic(err)
+	}
 }
 
 // Calculates and waits the time until the next update of UI update.
-func (s *Systems) wait() {
+// Returns early if the context is cancelled.
+func (s *Systems) wait(ctx context.Context) {
 	nextUpdate := s.nextUpdate
 
 	if (s.Paused || s.FPS > 0) && s.nextDraw.Before(nextUpdate) {
@@ -274,48 +783,93 @@ func (s *Systems) wait() {
 	wait := nextUpdate.Sub(t)
 
 	if wait > 0 {
-		time.Sleep(wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
 	}
 }
 
 // Update normal systems.
-func (s *Systems) updateSystemsSimple() bool {
-	for _, sys := range s.systems {
-		sys.Update(s.world)
+func (s *Systems) updateSystemsSimple() (err error) {
+	if s.Parallel {
+		return s.updateSystemsParallel()
+	}
+	var current System
+	defer s.recoverUpdate(&current, &err)
+
+	// Fields are copied to locals, so they need not be reloaded after each system update.
+	tick := s.tickRes.Get().Tick
+	infos, world, profiling := s.infos, s.world, s.Profiling
+	for i, sys := range s.systems {
+		current = sys
+		info := &infos[i]
+		if !info.always && !info.isDue(tick) {
+			continue
+		}
+		// Fast path for the most common case.
+		if !profiling && info.updater == nil {
+			sys.Update(world)
+			continue
+		}
+		if err := s.updateSystem(sys, info, tick); err != nil {
+			return err
+		}
 	}
-	return true
+	return nil
 }
 
 // Update normal systems.
-func (s *Systems) updateSystemsTimed() bool {
+func (s *Systems) updateSystemsTimed() (bool, error) {
 	update := false
-	if s.Paused {
+	if s.Paused && s.steps <= 0 {
 		update = !time.Now().Before(s.nextUpdate)
 		if update {
 			tps := s.limitedFps(s.TPS, 10)
 			s.nextUpdate = nextTime(s.nextUpdate, tps)
 		}
-		return false
+		return false, nil
 	}
 	if s.TPS <= 0 {
 		update = true
-		s.updateSystemsSimple()
+		if err := s.updateSystemsSimple(); err != nil {
+			return false, err
+		}
 	} else {
 		update = !time.Now().Before(s.nextUpdate)
 		if update {
 			s.nextUpdate = nextTime(s.nextUpdate, s.TPS)
-			s.updateSystemsSimple()
+			if err := s.updateSystemsSimple(); err != nil {
+				return false, err
+			}
 		}
 	}
-	return update
+	if update && s.Paused {
+		s.steps--
+	}
+	return update, nil
 }
 
 // Update ui systems.
 func (s *Systems) updateUISystemsSimple() {
-	for _, sys := range s.uiSystems {
+	for i, sys := range s.uiSystems {
+		if !s.uiInfos[i].enabled {
+			continue
+		}
+		if s.Profiling {
+			start := time.Now()
+			sys.UpdateUI(s.world)
+			s.profRes.Get().record(sys, true, time.Since(start))
+			continue
+		}
 		sys.UpdateUI(s.world)
 	}
-	for _, sys := range s.uiSystems {
+	for i, sys := range s.uiSystems {
+		if !s.uiInfos[i].enabled {
+			continue
+		}
 		sys.PostUpdateUI(s.world)
 	}
 }
@@ -324,6 +878,7 @@ func (s *Systems) updateUISystemsSimple() {
 func (s *Systems) updateUISystemsTimed(updated bool) {
 	if !s.Paused && s.FPS <= 0 {
 		if updated {
+			s.updateInterpolation()
 			s.updateUISystemsSimple()
 		}
 	} else {
@@ -333,34 +888,88 @@ func (s *Systems) updateUISystemsTimed(updated bool) {
 				fps = s.limitedFps(s.FPS, 30)
 			}
 			s.nextDraw = nextTime(s.nextDraw, fps)
+			s.updateInterpolation()
 			s.updateUISystemsSimple()
 		}
 	}
 }
 
+// Updates the interpolation fraction between the last and the next tick.
+func (s *Systems) updateInterpolation() {
+	interp := s.interpRes.Get()
+	if s.Paused || s.TPS <= 0 {
+		interp.Fraction = 1
+		return
+	}
+	dt := time.Duration(float64(time.Second) / s.TPS)
+	interp.Fraction = min(max(1-float64(time.Until(s.nextUpdate))/float64(dt), 0), 1)
+}
+
 // Finalize all systems.
 func (s *Systems) finalize() {
+	if err := s.finalizeE(); err != nil {
+		panic(err)
+	}
+}
+
+// Finalize all systems, and return all errors that occurred.
+// An error does not prevent the remaining systems from being finalized.
+func (s *Systems) finalizeE() error {
+	var err error
 	s.locked = true
 	for _, sys := range s.systems {
-		sys.Finalize(s.world)
+		err = errors.Join(err, s.finalizeSystem(sys))
 	}
 	for _, sys := range s.uiSystems {
 		sys.FinalizeUI(s.world)
 	}
 	s.locked = false
-	s.removeSystems()
+	s.finalized = true
+
+	s.toAdd = s.toAdd[:0]
+	s.uiToAdd = s.uiToAdd[:0]
+
+	return errors.Join(err, s.removeSystems())
 }
 
 // Run the app.
 func (s *Systems) run() {
+	if err := s.runE(); err != nil {
+		panic(err)
+	}
+}
+
+// Run the app, and return the first error that occurred.
+// Initialized systems are finalized even if an error occurs.
+func (s *Systems) runE() error {
+	return s.runContext(context.Background())
+}
+
+// Run the app until it terminates or the context is cancelled,
+// and return the first error that occurred, or the context's error.
+// Initialized systems are finalized even if an error occurs or the context is cancelled.
+func (s *Systems) runContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if !s.initialized {
-		s.initialize()
+		if err := s.initializeE(); err != nil {
+			return err
+		}
 	}
 
-	for s.update() {
+	var err error
+	for {
+		var running bool
+		if running, err = s.update(ctx); err != nil || !running {
+			break
+		}
+		if err = ctx.Err(); err != nil {
+			break
+		}
 	}
 
-	s.finalize()
+	return errors.Join(err, s.finalizeE())
 }
 
 // Removes all systems.
@@ -369,12 +978,25 @@ func (s *Systems) reset() {
 	s.uiSystems = []UISystem{}
 	s.toRemove = s.toRemove[:0]
 	s.uiToRemove = s.uiToRemove[:0]
+	s.toAdd = s.toAdd[:0]
+	s.uiToAdd = s.uiToAdd[:0]
+	s.info = nil
+	s.infos = nil
+	s.uiInfos = nil
+	s.toToggle = s.toToggle[:0]
+	s.steps = 0
+	s.batches = s.batches[:0]
+	s.planned = false
 
 	s.nextDraw = time.Time{}
 	s.nextUpdate = time.Time{}
+	s.lastUpdate = time.Time{}
+	s.accumulator = 0
 
 	s.initialized = false
+	s.finalized = false
 	s.tickRes = ecs.Resource[resource.Tick]{}
+	s.timeRes = ecs.Resource[resource.ModelTime]{}
 }
 
 // Calculates frame rate capped to target
@@ -2,6 +2,7 @@ package app
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark-tools/system"
@@ -79,6 +80,49 @@ func TestSystems(t *testing.T) {
 	}
 }
 
+func TestSystemsAddRunning(t *testing.T) {
+	app := New(1024)
+
+	counter := counterSystem{}
+	uiCounter := dualCounterSystem{}
+	app.AddSystem(&adderSystem{
+		Step:  3,
+		Add:   []System{&counter},
+		AddUI: []UISystem{&uiCounter},
+	})
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+
+	app.Run()
+
+	assert.Equal(t, 4, len(app.systems))
+	assert.Equal(t, 1, len(app.uiSystems))
+	assert.Equal(t, 0, len(app.toAdd))
+	assert.Equal(t, 0, len(app.uiToAdd))
+
+	assert.Equal(t, int64(3), counter.InitTick)
+	assert.Equal(t, 6, counter.Updates)
+	assert.True(t, counter.Finalized)
+
+	assert.Equal(t, int64(3), uiCounter.InitTick)
+	assert.Equal(t, 6, uiCounter.Updates)
+	assert.True(t, uiCounter.InitializedUI)
+	assert.True(t, uiCounter.Finalized)
+}
+
+func TestSystemsRemoveBeforeInit(t *testing.T) {
+	app := New(1024)
+
+	counter := counterSystem{}
+	app.AddSystem(&counter)
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+	app.RemoveSystem(&counter)
+	assert.False(t, counter.Finalized)
+
+	app.Run()
+	assert.False(t, counter.Finalized)
+	assert.Equal(t, 0, counter.Updates)
+}
+
 func TestSystemsInit(t *testing.T) {
 	app := New(1024)
 	app.TPS = 0
@@ -191,3 +235,256 @@ func (s *removerSystem) Update(w *ecs.World) {
 	s.step++
 }
 func (s *removerSystem) Finalize(w *ecs.World) {}
+
+type adderSystem struct {
+	Step  int64
+	Add   []System
+	AddUI []UISystem
+	step  int64
+}
+
+func (s *adderSystem) Initialize(w *ecs.World) {}
+func (s *adderSystem) Update(w *ecs.World) {
+	if s.step == s.Step {
+		systems := ecs.GetResource[Systems](w)
+		for _, sys := range s.Add {
+			systems.AddSystem(sys)
+		}
+		for _, sys := range s.AddUI {
+			systems.AddUISystem(sys)
+		}
+	}
+	s.step++
+}
+func (s *adderSystem) Finalize(w *ecs.World) {}
+
+type counterSystem struct {
+	InitTick  int64
+	Updates   int
+	Finalized bool
+}
+
+func (s *counterSystem) Initialize(w *ecs.World) {
+	s.InitTick = ecs.GetResource[resource.Tick](w).Tick
+}
+func (s *counterSystem) Update(w *ecs.World)   { s.Updates++ }
+func (s *counterSystem) Finalize(w *ecs.World) { s.Finalized = true }
+
+type dualCounterSystem struct {
+	counterSystem
+	InitializedUI bool
+}
+
+func (s *dualCounterSystem) InitializeUI(w *ecs.World) { s.InitializedUI = true }
+func (s *dualCounterSystem) UpdateUI(w *ecs.World)     {}
+func (s *dualCounterSystem) PostUpdateUI(w *ecs.World) {}
+func (s *dualCounterSystem) FinalizeUI(w *ecs.World)   {}
+
+func TestSystemsCatchUp(t *testing.T) {
+	app := New(1024)
+	app.TPS = 100
+	app.FPS = 200
+	app.MaxCatchUp = 3
+
+	ui := interpolationSystem{}
+	app.AddSystem(&sleepSystem{Tick: 5, Duration: 100 * time.Millisecond})
+	app.AddSystem(&system.FixedTermination{Steps: 20})
+	app.AddUISystem(&ui)
+
+	start := time.Now()
+	app.Run()
+
+	assert.Equal(t, int64(20), app.time.Tick)
+	assert.Greater(t, time.Since(start), 190*time.Millisecond)
+	assert.Greater(t, len(ui.Fractions), 0)
+	for _, f := range ui.Fractions {
+		assert.GreaterOrEqual(t, f, 0.0)
+		assert.LessOrEqual(t, f, 1.0)
+	}
+
+	app = New(1024)
+	app.MaxCatchUp = 3
+	app.FPS = -1
+	app.TPS = 100
+	ui = interpolationSystem{}
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+	app.AddUISystem(&ui)
+	app.Paused = true
+	app.AddUISystem(&uiTerminationSystem{Steps: 3})
+	app.Run()
+
+	assert.Equal(t, int64(0), app.time.Tick)
+	assert.Equal(t, []float64{1, 1, 1, 1}, ui.Fractions)
+}
+
+type sleepSystem struct {
+	Tick     int64
+	Duration time.Duration
+	tickRes  ecs.Resource[resource.Tick]
+}
+
+func (s *sleepSystem) Initialize(w *ecs.World) {
+	s.tickRes = ecs.NewResource[resource.Tick](w)
+}
+func (s *sleepSystem) Update(w *ecs.World) {
+	if s.tickRes.Get().Tick == s.Tick {
+		time.Sleep(s.Duration)
+	}
+}
+func (s *sleepSystem) Finalize(w *ecs.World) {}
+
+type interpolationSystem struct {
+	Fractions []float64
+	interpRes ecs.Resource[resource.Interpolation]
+}
+
+func (s *interpolationSystem) InitializeUI(w *ecs.World) {
+	s.interpRes = ecs.NewResource[resource.Interpolation](w)
+}
+func (s *interpolationSystem) UpdateUI(w *ecs.World) {
+	s.Fractions = append(s.Fractions, s.interpRes.Get().Fraction)
+}
+func (s *interpolationSystem) PostUpdateUI(w *ecs.World) {}
+func (s *interpolationSystem) FinalizeUI(w *ecs.World)   {}
+
+func TestSystemsFind(t *testing.T) {
+	app := New(1024)
+
+	counter := counterSystem{}
+	ui := uiSystem{}
+	app.AddSystem(&counter, WithName("counter"))
+	app.AddUISystem(&ui, WithName("ui"))
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+
+	sys, ok := app.Find("counter")
+	assert.True(t, ok)
+	assert.Same(t, &counter, sys)
+
+	sys, ok = app.Find("ui")
+	assert.True(t, ok)
+	assert.Same(t, &ui, sys)
+
+	_, ok = app.Find("foo")
+	assert.False(t, ok)
+	sys, ok = app.Find("")
+	assert.False(t, ok)
+	assert.Nil(t, sys)
+
+	assert.Panics(t, func() { app.AddSystem(&counterSystem{}, WithName("counter")) })
+
+	term, ok := FindSystem[*system.FixedTermination](&app.Systems)
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), term.Steps)
+
+	uiSys, ok := FindSystem[UISystem](&app.Systems)
+	assert.True(t, ok)
+	assert.Same(t, &ui, uiSys)
+
+	_, ok = FindSystem[*removerSystem](&app.Systems)
+	assert.False(t, ok)
+
+	assert.Equal(t, "counter", app.systemName(&counter))
+	assert.Equal(t, "*system.FixedTermination", app.systemName(term))
+}
+
+func TestSystemsEnable(t *testing.T) {
+	app := New(1024)
+
+	counter := counterSystem{}
+	ui := interpolationSystem{}
+	app.AddSystem(&counter)
+	app.AddUISystem(&ui)
+	app.AddSystem(&toggleSystem{Toggle: []any{&counter, &ui}, Disable: 3, Enable: 7})
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+	app.FPS = -1
+
+	app.Run()
+
+	assert.Equal(t, 6, counter.Updates)
+	assert.Equal(t, 6, len(ui.Fractions))
+	assert.True(t, counter.Finalized)
+	assert.True(t, app.IsEnabled(&counter))
+
+	app.Disable(&counter)
+	assert.False(t, app.IsEnabled(&counter))
+
+	assert.Panics(t, func() { app.Disable(&counterSystem{}) })
+	assert.Panics(t, func() { app.IsEnabled(&counterSystem{}) })
+}
+
+type toggleSystem struct {
+	Toggle  []any
+	Disable int64
+	Enable  int64
+	step    int64
+}
+
+func (s *toggleSystem) Initialize(w *ecs.World) {}
+func (s *toggleSystem) Update(w *ecs.World) {
+	systems := ecs.GetResource[Systems](w)
+	for _, sys := range s.Toggle {
+		if s.step == s.Disable {
+			systems.Disable(sys)
+		}
+		if s.step == s.Enable {
+			systems.Enable(sys)
+		}
+	}
+	s.step++
+}
+func (s *toggleSystem) Finalize(w *ecs.World) {}
+
+func TestSystemsStep(t *testing.T) {
+	app := New(1024)
+	app.TPS = 0
+	app.FPS = 0
+
+	counter := counterSystem{}
+	app.AddSystem(&counter)
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+	app.AddUISystem(&uiTerminationSystem{Steps: 5})
+
+	app.Step(3)
+	assert.True(t, app.Paused)
+	app.Run()
+
+	assert.Equal(t, 3, int(app.time.Tick))
+	assert.Equal(t, 3, counter.Updates)
+	assert.True(t, app.Paused)
+
+	assert.Panics(t, func() { app.Step(-1) })
+
+	app = New(1024)
+	counter = counterSystem{}
+	app.AddSystem(&counter)
+	app.Initialize()
+
+	app.Step(1)
+	app.Step(1)
+	app.Update()
+	app.Update()
+	app.Update()
+	assert.Equal(t, 2, int(app.time.Tick))
+	assert.Equal(t, 2, counter.Updates)
+	assert.True(t, app.Paused)
+}
+
+func TestSystemsModelTime(t *testing.T) {
+	app := New(1024)
+	app.modelTime.Start = 10
+	app.modelTime.Step = 0.5
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+	app.Run()
+
+	assert.Equal(t, 100, int(app.time.Tick))
+	assert.Equal(t, 60.0, app.modelTime.Time)
+	assert.Equal(t, 50.0, app.modelTime.Elapsed())
+
+	app.Reset()
+	assert.Equal(t, 1.0, app.modelTime.Step)
+	app.TPS = 1000
+	app.MaxCatchUp = 5
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+	app.Run()
+	assert.Equal(t, 10.0, app.modelTime.Time)
+}
@@ -0,0 +1,124 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/internal/rng"
+)
+
+// Batch runs replicated simulations in parallel.
+//
+// Each run gets its own [app.App], created by the Factory,
+// and a deterministic seed derived from the master Seed (see [DeriveSeed]).
+// After a run, Collect is called to extract results from the app.
+//
+// Errors and panics are reported per run, and don't stop other runs.
+type Batch[T any] struct {
+	Runs    int                                    // Number of runs.
+	Workers int                                    // Number of worker goroutines. Values <= 0 use the number of CPUs.
+	Seed    uint64                                 // Master seed, from which the seeds of runs are derived.
+	Factory func(run int, seed uint64) *app.App    // Creates the app for a run. Should seed the app with the given seed.
+	Collect func(run int, app *app.App) (T, error) // Extracts results after a run. Optional.
+}
+
+// Result of a single run.
+type Result[T any] struct {
+	Run   int    // Index of the run.
+	Seed  uint64 // Seed of the run.
+	Value T      // Value returned by Batch.Collect.
+	Err   error  // Error of the run, including recovered panics.
+}
+
+// Run all runs, and return the results in the order of runs.
+//
+// Returns an error that joins the errors of all failed runs.
+func (b *Batch[T]) Run() ([]Result[T], error) {
+	return b.RunContext(context.Background())
+}
+
+// RunContext runs all runs like [Batch.Run], but stops when the context is cancelled.
+// Runs that are in progress are stopped via [app.App.RunContext], and finalized.
+// Runs that did not start yet report the context's error.
+func (b *Batch[T]) RunContext(ctx context.Context) ([]Result[T], error) {
+	if b.Factory == nil {
+		panic("batch requires a Factory")
+	}
+	if b.Runs < 0 {
+		panic(fmt.Sprintf("number of runs must not be negative, got %d", b.Runs))
+	}
+	workers := b.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, b.Runs)
+
+	results := make([]Result[T], b.Runs)
+	runs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for run := range runs {
+				results[run] = b.runSingle(ctx, run)
+			}
+		}()
+	}
+	for run := range b.Runs {
+		runs <- run
+	}
+	close(runs)
+	wg.Wait()
+
+	var err error
+	for _, res := range results {
+		err = errors.Join(err, res.Err)
+	}
+	return results, err
+}
+
+// runSingle performs a single run, recovering from panics.
+func (b *Batch[T]) runSingle(ctx context.Context, run int) (result Result[T]) {
+	result.Run = run
+	result.Seed = DeriveSeed(b.Seed, run)
+
+	defer func() {
+		if r := recover(); r != nil {
+			result.Err = fmt.Errorf("run %d: panic: %v", run, r)
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		result.Err = fmt.Errorf("run %d: %w", run, err)
+		return
+	}
+
+	a := b.Factory(run, result.Seed)
+	if err := a.RunContext(ctx); err != nil {
+		result.Err = fmt.Errorf("run %d: %w", run, err)
+		return
+	}
+	if b.Collect != nil {
+		value, err := b.Collect(run, a)
+		if err != nil {
+			result.Err = fmt.Errorf("run %d: %w", run, err)
+			return
+		}
+		result.Value = value
+	}
+	return
+}
+
+// DeriveSeed derives the seed of a run from a master seed.
+//
+// Seeds are well distributed also for consecutive master seeds and runs.
+// Uses the SplitMix64 algorithm.
+func DeriveSeed(master uint64, run int) uint64 {
+	return rng.SplitMix64(master + uint64(run)*0x9e3779b97f4a7c15)
+}
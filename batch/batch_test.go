@@ -0,0 +1,119 @@
+package batch_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/batch"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+	b := batch.Batch[uint64]{
+		Runs:    8,
+		Workers: 3,
+		Seed:    42,
+		Factory: func(run int, seed uint64) *app.App {
+			if run == 6 {
+				panic("factory panic")
+			}
+			app := app.New(1024).Seed(seed)
+			if run == 3 {
+				app.AddSystem(&system.CallbackTermination{
+					Callback: func(t int64) bool { panic("test panic") },
+				})
+			}
+			app.AddSystem(&system.FixedTermination{Steps: 10})
+			return app
+		},
+		Collect: func(run int, app *app.App) (uint64, error) {
+			if run == 5 {
+				return 0, errors.New("test error")
+			}
+			rand := ecs.GetResource[resource.Rand](&app.World)
+			return rand.Uint64(), nil
+		},
+	}
+
+	results, err := b.Run()
+	assert.ErrorContains(t, err, "run 3: updating system *system.CallbackTermination at tick 0: panic: test panic")
+	assert.ErrorContains(t, err, "run 5: test error")
+	assert.ErrorContains(t, err, "run 6: panic: factory panic")
+
+	assert.Equal(t, 8, len(results))
+	for i, res := range results {
+		assert.Equal(t, i, res.Run)
+		assert.Equal(t, batch.DeriveSeed(42, i), res.Seed)
+		if i == 3 || i == 5 || i == 6 {
+			assert.NotNil(t, res.Err)
+		} else {
+			assert.Nil(t, res.Err)
+			assert.NotZero(t, res.Value)
+		}
+	}
+
+	results2, _ := b.Run()
+	assert.Equal(t, results[0].Value, results2[0].Value)
+	assert.NotEqual(t, results[0].Value, results[1].Value)
+}
+
+func TestBatchContext(t *testing.T) {
+	b := batch.Batch[int]{
+		Runs: 4,
+		Factory: func(run int, seed uint64) *app.App {
+			return app.New(1024).Seed(seed)
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := b.RunContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 4, len(results))
+
+	b = batch.Batch[int]{Runs: 4}
+	assert.Panics(t, func() { _, _ = b.Run() })
+
+	b = batch.Batch[int]{
+		Runs:    -1,
+		Factory: func(run int, seed uint64) *app.App { return app.New(1024) },
+	}
+	assert.Panics(t, func() { _, _ = b.Run() })
+}
+
+func TestDeriveSeed(t *testing.T) {
+	assert.Equal(t, batch.DeriveSeed(1, 2), batch.DeriveSeed(1, 2))
+	assert.NotEqual(t, batch.DeriveSeed(1, 2), batch.DeriveSeed(2, 1))
+	assert.NotEqual(t, batch.DeriveSeed(0, 0), batch.DeriveSeed(0, 1))
+}
+
+func ExampleBatch() {
+	b := batch.Batch[int64]{
+		Runs: 10,    // Number of replicates.
+		Seed: 12345, // Master seed.
+		Factory: func(run int, seed uint64) *app.App {
+			// Create and set up a seeded app for the run.
+			app := app.New(1024).Seed(seed)
+			app.AddSystem(&system.FixedTermination{Steps: 100})
+			return app
+		},
+		Collect: func(run int, app *app.App) (int64, error) {
+			// Extract results from the app after the run.
+			tick := ecs.GetResource[resource.Tick](&app.World)
+			return tick.Tick, nil
+		},
+	}
+
+	results, err := b.Run()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(results[0].Value)
+	// Output: 100
+}
@@ -0,0 +1,3 @@
+// Package batch provides a runner for replicated simulations,
+// executed in parallel on a pool of worker goroutines.
+package batch
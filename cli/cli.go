@@ -0,0 +1,383 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/experiment"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Runner is a command line interface around a model.
+//
+// The model is created by Factory, for every run.
+// The runner applies the settings from the command line to the created app.
+// It adds an [experiment.Parameters] resource with the values given by -param flags.
+// In headless mode, and always in batch and sweep mode, the runner removes all UI systems from the app.
+// This includes systems that are both a [app.System] and a [app.UISystem].
+// In batch and sweep mode, data from the Observer is collected from all runs and written as a CSV table.
+type Runner struct {
+	Name           string                            // Name of the program, for usage messages.
+	Factory        func(settings *Settings) *app.App // Creates an app.
+	Observer       func() observer.Row               // Creates an observer for data collection in batch and sweep mode. Optional.
+	UpdateInterval int                               // Interval for collecting observer data, in ticks. Default 1.
+	Final          bool                              // Whether to collect observer data only at the end of each run.
+	Stdout         io.Writer                         // Writer for output. Optional, defaults to [os.Stdout].
+	Stderr         io.Writer                         // Writer for usage and errors. Optional, defaults to [os.Stderr].
+}
+
+// Settings resolved from the command line.
+type Settings struct {
+	Command    string                 // The subcommand: run, batch or sweep.
+	Seed       *uint64                // Random seed, or master seed in batch and sweep mode. Nil for seeding from the current time.
+	TPS        *float64               // Ticks per second. Nil to keep the app's value.
+	Steps      int64                  // Number of steps, applied to the app's [system.FixedTermination], which is required. 0 to keep the app's termination.
+	Out        string                 // Directory for the output of reporters, keeping their relative paths. Empty to keep the reporters' paths.
+	Headless   bool                   // Whether to run without UI systems. Always true in batch and sweep mode.
+	Quiet      bool                   // Whether to suppress printing the settings and termination.
+	Runs       int                    // Number of runs in batch mode.
+	Replicates int                    // Number of replicates per parameter combination in sweep mode.
+	Workers    int                    // Number of parallel workers in batch and sweep mode. Values <= 0 use the number of CPUs.
+	Parameters []experiment.Parameter // Parameters given by -param flags.
+}
+
+// Main runs the command line interface with the program's arguments, and exits the program on error.
+func (r *Runner) Main() {
+	if err := r.Run(os.Args[1:]); err != nil {
+		fmt.Fprintf(r.stderr(), "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// Run the command line interface with the given arguments, excluding the program name.
+//
+// Runs are stopped gracefully on SIGINT (Ctrl+C) or SIGTERM, see [app.InterruptContext].
+func (r *Runner) Run(args []string) error {
+	if r.Factory == nil {
+		panic("runner requires a Factory")
+	}
+	if len(args) == 0 {
+		r.usage()
+		return errors.New("missing command")
+	}
+
+	settings, err := r.parse(args[0], args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if !settings.Quiet {
+		data, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(r.stdout(), "Settings: %s\n", data)
+	}
+
+	ctx, stop := app.InterruptContext(context.Background())
+	defer stop()
+
+	switch settings.Command {
+	case "run":
+		return r.runSingle(ctx, settings)
+	default:
+		return r.runExperiment(ctx, settings)
+	}
+}
+
+// parse the flags of a subcommand.
+func (r *Runner) parse(command string, args []string) (*Settings, error) {
+	settings := Settings{Command: command}
+	flags := flag.NewFlagSet(fmt.Sprintf("%s %s", r.name(), command), flag.ContinueOnError)
+	flags.SetOutput(r.stderr())
+
+	var seed uint64
+	var tps float64
+	flags.Uint64Var(&seed, "seed", 0, "random seed (default: seed from current time)")
+	flags.Float64Var(&tps, "tps", 0, "ticks per second, 0 for unlimited (default: app's TPS)")
+	flags.Int64Var(&settings.Steps, "steps", 0, "number of steps (default: app's termination)")
+	flags.StringVar(&settings.Out, "out", "", "output directory for reporters")
+	flags.BoolVar(&settings.Headless, "headless", false, "run without UI systems")
+	flags.BoolVar(&settings.Quiet, "quiet", false, "don't print settings and termination")
+	flags.Func("param", "parameter as name=value, name=v1,v2,... or name=start:end:n (repeatable)", func(s string) error {
+		param, err := parseParameter(s)
+		if err != nil {
+			return err
+		}
+		settings.Parameters = append(settings.Parameters, param)
+		return nil
+	})
+
+	switch command {
+	case "run":
+	case "batch":
+		flags.IntVar(&settings.Runs, "runs", 10, "number of runs")
+		flags.IntVar(&settings.Workers, "workers", 0, "number of parallel workers (default: number of CPUs)")
+	case "sweep":
+		flags.IntVar(&settings.Replicates, "replicates", 1, "number of replicates per parameter combination")
+		flags.IntVar(&settings.Workers, "workers", 0, "number of parallel workers (default: number of CPUs)")
+	case "help", "-h", "-help", "--help":
+		r.usage()
+		return nil, flag.ErrHelp
+	default:
+		r.usage()
+		return nil, fmt.Errorf("unknown command '%s'", command)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			settings.Seed = &seed
+		case "tps":
+			settings.TPS = &tps
+		}
+	})
+
+	if command != "sweep" {
+		for _, p := range settings.Parameters {
+			if len(p.Values) != 1 {
+				return nil, fmt.Errorf("parameter '%s' has %d values, use command sweep for multiple values", p.Name, len(p.Values))
+			}
+		}
+	} else if len(settings.Parameters) == 0 {
+		return nil, errors.New("command sweep requires at least one -param")
+	}
+	if command != "run" {
+		// UI systems can't be used from the worker goroutines of batch and sweep runs.
+		settings.Headless = true
+	}
+	if command == "batch" && settings.Runs < 1 {
+		return nil, fmt.Errorf("number of runs must be positive, got %d", settings.Runs)
+	}
+	return &settings, nil
+}
+
+// runSingle performs a single run.
+func (r *Runner) runSingle(ctx context.Context, settings *Settings) error {
+	a := r.Factory(settings)
+	params := experiment.Parameters{}
+	for _, p := range settings.Parameters {
+		params.Names = append(params.Names, p.Name)
+		params.Values = append(params.Values, p.Values[0])
+	}
+	res := ecs.NewResource[experiment.Parameters](&a.World)
+	if res.Has() {
+		*res.Get() = params
+	} else {
+		res.Add(&params)
+	}
+	if settings.Seed != nil {
+		a.Seed(*settings.Seed)
+	}
+	if err := r.configure(a, settings, settings.Out); err != nil {
+		return err
+	}
+
+	if !settings.Quiet {
+		fmt.Fprintf(r.stdout(), "Seed: %d\n", ecs.GetResource[resource.Rand](&a.World).Seed())
+	}
+	err := a.RunContext(ctx)
+	if !settings.Quiet {
+		tick := ecs.GetResource[resource.Tick](&a.World).Tick
+		term := ecs.GetResource[resource.Termination](&a.World)
+		fmt.Fprintf(r.stdout(), "Finished after %d ticks: %s\n", tick, term.Reason)
+	}
+	return err
+}
+
+// runExperiment performs a batch run or a parameter sweep.
+func (r *Runner) runExperiment(ctx context.Context, settings *Settings) error {
+	seed := uint64(time.Now().UnixNano())
+	if settings.Seed != nil {
+		seed = *settings.Seed
+	}
+	replicates := settings.Replicates
+	if settings.Command == "batch" {
+		replicates = settings.Runs
+	}
+
+	exp := experiment.Experiment{
+		Parameters:     settings.Parameters,
+		Replicates:     replicates,
+		Workers:        settings.Workers,
+		Seed:           seed,
+		Observer:       r.Observer,
+		UpdateInterval: r.UpdateInterval,
+		Final:          r.Final,
+	}
+	exp.Factory = func(run int, params *experiment.Parameters, runSeed uint64) *app.App {
+		a := r.Factory(settings)
+		a.Seed(runSeed)
+		dir := settings.Out
+		if dir != "" {
+			dir = filepath.Join(dir, fmt.Sprintf("run-%04d", run))
+		}
+		if err := r.configure(a, settings, dir); err != nil {
+			// Panics are reported as errors of the run by the batch runner.
+			panic(err)
+		}
+		return a
+	}
+
+	if !settings.Quiet {
+		fmt.Fprintf(r.stdout(), "Master seed: %d, runs: %d\n", seed, exp.Runs())
+	}
+	table, err := exp.RunContext(ctx)
+	if r.Observer != nil {
+		if writeErr := r.writeTable(table, settings); writeErr != nil {
+			return errors.Join(err, writeErr)
+		}
+	}
+	return err
+}
+
+// configure applies the settings to an app.
+func (r *Runner) configure(a *app.App, settings *Settings, dir string) error {
+	if settings.Headless {
+		// Removal modifies the app's slice of UI systems.
+		for _, sys := range slices.Clone(a.UISystems()) {
+			a.RemoveUISystem(sys)
+		}
+	}
+	if settings.TPS != nil {
+		a.TPS = *settings.TPS
+	}
+	if settings.Steps > 0 {
+		term, ok := app.FindSystem[*system.FixedTermination](&a.Systems)
+		if !ok {
+			return errors.New("flag -steps requires a system.FixedTermination in the app")
+		}
+		term.Steps = settings.Steps
+	}
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	var err error
+	for _, sys := range a.Systems.Systems() {
+		switch sys := sys.(type) {
+		case *reporter.CSV:
+			if sys.File, err = outPath(dir, sys.File); err != nil {
+				return err
+			}
+		case *reporter.SnapshotCSV:
+			if sys.FilePattern, err = outPath(dir, sys.FilePattern); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// outPath redirects a reporter's output file to the output directory,
+// keeping its relative directory structure.
+func outPath(dir, file string) (string, error) {
+	if !filepath.IsLocal(file) {
+		return "", fmt.Errorf("can't redirect output file '%s' to -out: not a local relative path", file)
+	}
+	return filepath.Join(dir, file), nil
+}
+
+// writeTable writes the data collected in batch or sweep mode.
+// Writes to a file in the output directory if given, or to stdout otherwise.
+func (r *Runner) writeTable(table *experiment.Table, settings *Settings) error {
+	if settings.Out == "" {
+		return table.WriteCSV(r.stdout(), ",")
+	}
+	if err := os.MkdirAll(settings.Out, os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(settings.Out, settings.Command+".csv"))
+	if err != nil {
+		return err
+	}
+	if err := table.WriteCSV(file, ","); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	return file.Close()
+}
+
+// parseParameter parses a parameter in the formats name=value, name=v1,v2,... or name=start:end:n.
+func parseParameter(s string) (experiment.Parameter, error) {
+	name, values, ok := strings.Cut(s, "=")
+	if !ok || name == "" || values == "" {
+		return experiment.Parameter{}, fmt.Errorf("invalid parameter '%s', expected name=values", s)
+	}
+	if parts := strings.Split(values, ":"); len(parts) == 3 {
+		start, err1 := strconv.ParseFloat(parts[0], 64)
+		end, err2 := strconv.ParseFloat(parts[1], 64)
+		n, err3 := strconv.Atoi(parts[2])
+		if err := errors.Join(err1, err2, err3); err != nil || n < 1 {
+			return experiment.Parameter{}, fmt.Errorf("invalid parameter range '%s', expected start:end:n", values)
+		}
+		return experiment.Linear(name, start, end, n), nil
+	}
+	param := experiment.Parameter{Name: name}
+	for _, v := range strings.Split(values, ",") {
+		value, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return experiment.Parameter{}, fmt.Errorf("invalid value '%s' of parameter '%s'", v, name)
+		}
+		param.Values = append(param.Values, value)
+	}
+	return param, nil
+}
+
+func (r *Runner) usage() {
+	fmt.Fprintf(r.stderr(), `Usage: %s <command> [flags]
+
+Commands:
+  run     Runs a single simulation.
+  batch   Runs replicated simulations in parallel.
+  sweep   Runs a parameter sweep, with the parameters given by -param flags.
+
+Run '%s <command> -h' for the flags of a command.
+`, r.name(), r.name())
+}
+
+func (r *Runner) name() string {
+	if r.Name == "" {
+		return filepath.Base(os.Args[0])
+	}
+	return r.Name
+}
+
+func (r *Runner) stdout() io.Writer {
+	if r.Stdout == nil {
+		return os.Stdout
+	}
+	return r.Stdout
+}
+
+func (r *Runner) stderr() io.Writer {
+	if r.Stderr == nil {
+		return os.Stderr
+	}
+	return r.Stderr
+}
@@ -0,0 +1,193 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/cli"
+	"github.com/mlange-42/ark-tools/experiment"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRunner(stdout *bytes.Buffer) *cli.Runner {
+	return &cli.Runner{
+		Name: "model",
+		Factory: func(settings *cli.Settings) *app.App {
+			a := app.New(1024)
+			a.AddSystem(&system.FixedTermination{Steps: 100})
+			a.AddSystem(&reporter.CSV{Observer: &paramObserver{}, File: "out/data.csv"})
+			a.AddUISystem(&uiSystem{})
+			return a
+		},
+		Observer: func() observer.Row { return &paramObserver{} },
+		Final:    true,
+		Stdout:   stdout,
+		Stderr:   &bytes.Buffer{},
+	}
+}
+
+func TestRunnerRun(t *testing.T) {
+	dir := t.TempDir()
+	stdout := bytes.Buffer{}
+	r := newRunner(&stdout)
+
+	err := r.Run([]string{"run", "-headless", "-seed", "42", "-tps", "0", "-steps", "10", "-out", dir, "-param", "a=2"})
+	assert.Nil(t, err)
+	assert.Contains(t, stdout.String(), `"Seed": 42`)
+	assert.Contains(t, stdout.String(), "Seed: 42\n")
+	assert.Contains(t, stdout.String(), "Finished after 10 ticks: reached 10 ticks")
+
+	data, err := os.ReadFile(filepath.Join(dir, "out", "data.csv"))
+	assert.Nil(t, err)
+	assert.Equal(t, 11, len(strings.Split(strings.TrimSpace(string(data)), "\n")))
+	assert.True(t, strings.HasPrefix(string(data), "t,a\n0,2\n"))
+
+	stdout.Reset()
+	assert.Nil(t, r.Run([]string{"run", "-quiet", "-headless", "-steps", "5", "-out", dir}))
+	assert.Empty(t, stdout.String())
+
+	// UI systems are kept without -headless.
+	assert.Panics(t, func() { _ = r.Run([]string{"run", "-quiet", "-steps", "5", "-out", dir}) })
+}
+
+func TestRunnerFactoryParameters(t *testing.T) {
+	dir := t.TempDir()
+	r := newRunner(&bytes.Buffer{})
+	factory := r.Factory
+	r.Factory = func(settings *cli.Settings) *app.App {
+		a := factory(settings)
+		ecs.AddResource(&a.World, &experiment.Parameters{Names: []string{"a"}, Values: []float64{-1}})
+		return a
+	}
+
+	assert.Nil(t, r.Run([]string{"run", "-quiet", "-headless", "-steps", "1", "-out", dir, "-param", "a=3"}))
+	data, err := os.ReadFile(filepath.Join(dir, "out", "data.csv"))
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "t,a\n0,3\n"))
+}
+
+func TestRunnerBatch(t *testing.T) {
+	dir := t.TempDir()
+	stdout := bytes.Buffer{}
+	r := newRunner(&stdout)
+
+	err := r.Run([]string{"batch", "-seed", "1", "-steps", "10", "-runs", "3", "-workers", "2", "-out", dir, "-param", "a=5"})
+	assert.Nil(t, err)
+	assert.Contains(t, stdout.String(), "Master seed: 1, runs: 3")
+
+	for _, run := range []string{"run-0000", "run-0001", "run-0002"} {
+		_, err := os.Stat(filepath.Join(dir, run, "out", "data.csv"))
+		assert.Nil(t, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "batch.csv"))
+	assert.Nil(t, err)
+	assert.Equal(t, "run,replicate,a,t,a\n0,0,5,10,5\n1,1,5,10,5\n2,2,5,10,5\n", string(data))
+}
+
+func TestRunnerSweep(t *testing.T) {
+	stdout := bytes.Buffer{}
+	r := newRunner(&stdout)
+
+	dir := t.TempDir()
+	err := r.Run([]string{"sweep", "-quiet", "-seed", "1", "-steps", "10", "-replicates", "2", "-out", dir, "-param", "a=0:1:2"})
+	assert.Nil(t, err)
+	assert.Empty(t, stdout.String())
+
+	data, err := os.ReadFile(filepath.Join(dir, "sweep.csv"))
+	assert.Nil(t, err)
+	assert.Equal(t, "run,replicate,a,t,a\n0,0,0,10,0\n1,1,0,10,0\n2,0,1,10,1\n3,1,1,10,1\n", string(data))
+}
+
+func TestRunnerErrors(t *testing.T) {
+	r := newRunner(&bytes.Buffer{})
+
+	assert.NotNil(t, r.Run([]string{}))
+	assert.NotNil(t, r.Run([]string{"foo"}))
+	assert.Nil(t, r.Run([]string{"help"}))
+	assert.Nil(t, r.Run([]string{"run", "-h"}))
+	assert.NotNil(t, r.Run([]string{"run", "-foo"}))
+	assert.NotNil(t, r.Run([]string{"run", "extra"}))
+	assert.NotNil(t, r.Run([]string{"run", "-param", "a=1,2"}))
+	assert.NotNil(t, r.Run([]string{"run", "-param", "a"}))
+	assert.NotNil(t, r.Run([]string{"run", "-param", "a=x"}))
+	assert.NotNil(t, r.Run([]string{"run", "-param", "a=0:1:x"}))
+	assert.NotNil(t, r.Run([]string{"batch", "-runs", "0"}))
+	assert.NotNil(t, r.Run([]string{"sweep"}))
+
+	dir := t.TempDir()
+	r.Factory = func(settings *cli.Settings) *app.App {
+		a := app.New(1024)
+		a.AddSystem(&system.CallbackTermination{Callback: func(t int64) bool { return t >= 10 }})
+		return a
+	}
+	assert.EqualError(t, r.Run([]string{"run", "-quiet", "-steps", "5", "-out", dir}),
+		"flag -steps requires a system.FixedTermination in the app")
+
+	r.Factory = func(settings *cli.Settings) *app.App {
+		a := app.New(1024)
+		a.AddSystem(&system.FixedTermination{Steps: 10})
+		a.AddSystem(&reporter.CSV{Observer: &paramObserver{}, File: "../data.csv"})
+		return a
+	}
+	assert.ErrorContains(t, r.Run([]string{"run", "-quiet", "-out", dir}), "not a local relative path")
+
+	r.Factory = nil
+	assert.Panics(t, func() { _ = r.Run([]string{"run"}) })
+}
+
+func ExampleRunner() {
+	runner := cli.Runner{
+		Name: "model",
+		Factory: func(settings *cli.Settings) *app.App {
+			myApp := app.New(1024)
+			myApp.AddSystem(&system.FixedTermination{Steps: 100})
+			return myApp
+		},
+	}
+
+	// In a real program, call runner.Main() to use the program's arguments.
+	err := runner.Run([]string{"run", "-quiet", "-seed", "42", "-steps", "10"})
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+}
+
+// paramObserver reports the value of parameter "a", or 0 if it is not set.
+type paramObserver struct {
+	params ecs.Resource[experiment.Parameters]
+}
+
+func (o *paramObserver) Initialize(w *ecs.World) {
+	o.params = ecs.NewResource[experiment.Parameters](w)
+}
+
+func (o *paramObserver) Update(w *ecs.World) {}
+
+func (o *paramObserver) Header() []string {
+	return []string{"a"}
+}
+
+func (o *paramObserver) Values(w *ecs.World) []float64 {
+	params := o.params.Get()
+	if !params.Has("a") {
+		return []float64{0}
+	}
+	return []float64{params.Get("a")}
+}
+
+// uiSystem panics on initialization, to check that UI systems are removed in headless mode.
+type uiSystem struct{}
+
+func (s *uiSystem) InitializeUI(w *ecs.World) { panic("UI system initialized") }
+func (s *uiSystem) UpdateUI(w *ecs.World)     {}
+func (s *uiSystem) PostUpdateUI(w *ecs.World) {}
+func (s *uiSystem) FinalizeUI(w *ecs.World)   {}
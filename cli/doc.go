@@ -0,0 +1,14 @@
+// Package cli provides a standard command line interface for models built with ark-tools.
+//
+// A [Runner] wraps a factory for [github.com/mlange-42/ark-tools/app.App] instances,
+// and provides the subcommands run, batch and sweep.
+// Flags allow to override the random seed, TPS and the number of steps,
+// to redirect the output of reporters to a directory, and to run in headless mode.
+// The resolved settings are printed before running.
+//
+// Usage:
+//
+//	model run [flags]     Runs a single simulation.
+//	model batch [flags]   Runs replicated simulations in parallel.
+//	model sweep [flags]   Runs a parameter sweep, with the parameters given by -param flags.
+package cli
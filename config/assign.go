@@ -0,0 +1,287 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Error in a configuration, with the path of the offending entry.
+type Error struct {
+	Path string // Path of the offending entry, like "systems[1].fields.File".
+	Err  error  // The underlying error.
+}
+
+// Error returns the error message, prefixed by the path.
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// errorf creates an [Error] for the given path.
+func errorf(path string, format string, args ...any) error {
+	return &Error{Path: path, Err: fmt.Errorf(format, args...)}
+}
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	timeType     = reflect.TypeFor[time.Time]()
+)
+
+// assign sets a value decoded from JSON or YAML to a Go value, using reflection.
+func (r *Registry) assign(path string, dst reflect.Value, src any) error {
+	if src == nil {
+		dst.SetZero()
+		return nil
+	}
+
+	switch dst.Type() {
+	case durationType:
+		if s, ok := src.(string); ok {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return errorf(path, "invalid duration '%s'", s)
+			}
+			dst.SetInt(int64(d))
+			return nil
+		}
+	case timeType:
+		s, ok := src.(string)
+		if !ok {
+			return errorf(path, "expected a time in RFC 3339 format, got %T", src)
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return errorf(path, "invalid time '%s', expected RFC 3339 format", s)
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		if dst.NumMethod() == 0 {
+			dst.Set(reflect.ValueOf(src))
+			return nil
+		}
+		return r.assignObserver(path, dst, src)
+	case reflect.Pointer:
+		value := reflect.New(dst.Type().Elem())
+		if err := r.assign(path, value.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(value)
+		return nil
+	case reflect.Struct:
+		return r.assignStruct(path, dst, src)
+	case reflect.Slice:
+		items, ok := src.([]any)
+		if !ok {
+			return errorf(path, "expected a list, got %T", src)
+		}
+		value := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := r.assign(fmt.Sprintf("%s[%d]", path, i), value.Index(i), item); err != nil {
+				return err
+			}
+		}
+		dst.Set(value)
+		return nil
+	case reflect.Array:
+		items, ok := src.([]any)
+		if !ok || len(items) != dst.Len() {
+			return errorf(path, "expected a list of length %d", dst.Len())
+		}
+		for i, item := range items {
+			if err := r.assign(fmt.Sprintf("%s[%d]", path, i), dst.Index(i), item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if dst.Type().Key().Kind() != reflect.String {
+			return errorf(path, "unsupported map key type %s", dst.Type().Key())
+		}
+		entries, ok := src.(map[string]any)
+		if !ok {
+			return errorf(path, "expected a mapping, got %T", src)
+		}
+		value := reflect.MakeMapWithSize(dst.Type(), len(entries))
+		for _, key := range sortedEntries(entries) {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := r.assign(joinPath(path, key), elem, entries[key]); err != nil {
+				return err
+			}
+			value.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(value)
+		return nil
+	case reflect.String:
+		s, ok := src.(string)
+		if !ok {
+			return errorf(path, "expected a string, got %T", src)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return errorf(path, "expected a boolean, got %T", src)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, ok := toFloat(src)
+		if !ok || f != math.Trunc(f) || dst.OverflowInt(int64(f)) {
+			return errorf(path, "expected an integer in the range of %s, got %v", dst.Type(), src)
+		}
+		dst.SetInt(int64(f))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, ok := toUint(src)
+		if !ok || dst.OverflowUint(u) {
+			return errorf(path, "expected a non-negative integer in the range of %s, got %v", dst.Type(), src)
+		}
+		dst.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, ok := toFloat(src)
+		if !ok {
+			return errorf(path, "expected a number, got %T", src)
+		}
+		dst.SetFloat(f)
+		return nil
+	}
+	return errorf(path, "unsupported field type %s", dst.Type())
+}
+
+// assignStruct sets the fields of a struct from a mapping.
+// Field names are matched case-insensitively. Unexported fields can't be set.
+func (r *Registry) assignStruct(path string, dst reflect.Value, src any) error {
+	entries, ok := src.(map[string]any)
+	if !ok {
+		return errorf(path, "expected a mapping, got %T", src)
+	}
+	tp := dst.Type()
+	for _, key := range sortedEntries(entries) {
+		idx := slices.IndexFunc(reflect.VisibleFields(tp), func(f reflect.StructField) bool {
+			return f.IsExported() && !f.Anonymous && strings.EqualFold(f.Name, key)
+		})
+		if idx < 0 {
+			return errorf(joinPath(path, key), "unknown field of %s", tp)
+		}
+		field := reflect.VisibleFields(tp)[idx]
+		if err := r.assign(joinPath(path, key), dst.FieldByIndex(field.Index), entries[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignObserver creates an observer from a mapping with type and fields, and assigns it to an interface.
+func (r *Registry) assignObserver(path string, dst reflect.Value, src any) error {
+	obs, err := r.create(path, r.observers, "observer", src)
+	if err != nil {
+		return err
+	}
+	value := reflect.ValueOf(obs)
+	if !value.Type().Implements(dst.Type()) {
+		return errorf(joinPath(path, "type"), "observer type %s does not implement %s", value.Type(), dst.Type())
+	}
+	dst.Set(value)
+	return nil
+}
+
+// create creates a registered system or observer from a mapping with type and fields.
+func (r *Registry) create(path string, types map[string]func() any, kind string, src any) (any, error) {
+	entries, ok := src.(map[string]any)
+	if !ok {
+		return nil, errorf(path, "expected a mapping with %s type and fields, got %T", kind, src)
+	}
+	for _, key := range sortedEntries(entries) {
+		if key != "type" && key != "fields" {
+			return nil, errorf(joinPath(path, key), "unknown key, expected 'type' or 'fields'")
+		}
+	}
+	name, ok := entries["type"].(string)
+	if !ok {
+		return nil, errorf(joinPath(path, "type"), "missing %s type", kind)
+	}
+	factory, ok := types[name]
+	if !ok {
+		return nil, errorf(joinPath(path, "type"), "unknown %s type '%s'", kind, name)
+	}
+	obj := factory()
+	if fields, ok := entries["fields"]; ok {
+		if err := r.assignStruct(joinPath(path, "fields"), reflect.ValueOf(obj).Elem(), fields); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// toFloat converts a decoded number to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toUint converts a decoded number to uint64, without loss of precision for large integers.
+func toUint(v any) (uint64, bool) {
+	switch n := v.(type) {
+	case int:
+		return uint64(n), n >= 0
+	case int64:
+		return uint64(n), n >= 0
+	case uint64:
+		return n, true
+	case float64:
+		return uint64(n), n >= 0 && n == math.Trunc(n) && n < math.MaxUint64
+	case json.Number:
+		var u uint64
+		_, err := fmt.Sscan(string(n), &u)
+		return u, err == nil
+	}
+	return 0, false
+}
+
+// joinPath appends a key to a path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedEntries returns the keys of a mapping, sorted for deterministic errors.
+func sortedEntries(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/system"
+	"gopkg.in/yaml.v3"
+)
+
+// Format of a configuration file.
+type Format uint8
+
+// Configuration formats.
+const (
+	JSON Format = iota // JSON format.
+	YAML               // YAML format.
+)
+
+// Config of an app.
+//
+// In configuration files, field names are matched case-insensitively.
+type Config struct {
+	TPS     float64        // Ticks per second. Optional, see [app.Systems].
+	FPS     float64        // Frames per second. Optional, see [app.Systems].
+	Seed    *uint64        // Random seed. Optional, seeds from the current time if not given.
+	Steps   int64          // Number of ticks after which to terminate, using a [system.FixedTermination]. Optional.
+	Systems []SystemConfig // Systems of the app, in the order in which they are added.
+}
+
+// SystemConfig is the configuration of a single system.
+type SystemConfig struct {
+	Type   string         // Name of the system type in the [Registry].
+	Name   string         // Name of the system, see [app.WithName]. Optional.
+	Stage  string         // Stage of the system, like "PreUpdate", see [app.InStage]. Optional.
+	Fields map[string]any // Field values of the system. Observers are given as mappings with type and fields.
+}
+
+// Read a [Config] in the given format.
+func (r *Registry) Read(reader io.Reader, format Format) (*Config, error) {
+	var data any
+	switch format {
+	case JSON:
+		decoder := json.NewDecoder(reader)
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return nil, &Error{Err: fmt.Errorf("reading JSON: %w", err)}
+		}
+	case YAML:
+		if err := yaml.NewDecoder(reader).Decode(&data); err != nil && err != io.EOF {
+			return nil, &Error{Err: fmt.Errorf("reading YAML: %w", err)}
+		}
+	default:
+		panic(fmt.Sprintf("unknown config format %d", format))
+	}
+
+	cfg := Config{}
+	if data == nil {
+		return &cfg, nil
+	}
+	if err := r.assign("", reflect.ValueOf(&cfg).Elem(), data); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ReadFile reads a [Config] from a file.
+// The format is determined from the file extension, which must be .json, .yaml or .yml.
+func (r *Registry) ReadFile(path string) (*Config, error) {
+	var format Format
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = JSON
+	case ".yaml", ".yml":
+		format = YAML
+	default:
+		return nil, fmt.Errorf("unknown config file extension in '%s', expected .json, .yaml or .yml", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := r.Read(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Build creates an app from a [Config].
+func (r *Registry) Build(cfg *Config) (*app.App, error) {
+	a := app.New(1024)
+	if cfg.Seed != nil {
+		a.Seed(*cfg.Seed)
+	}
+	if cfg.TPS != 0 {
+		a.TPS = cfg.TPS
+	}
+	if cfg.FPS != 0 {
+		a.FPS = cfg.FPS
+	}
+
+	names := map[string]int{}
+	for i, sysCfg := range cfg.Systems {
+		path := fmt.Sprintf("systems[%d]", i)
+		src := map[string]any{"type": sysCfg.Type}
+		if sysCfg.Fields != nil {
+			src["fields"] = sysCfg.Fields
+		}
+		sys, err := r.create(path, r.systems, "system", src)
+		if err != nil {
+			return nil, err
+		}
+
+		options := []app.Option{}
+		if sysCfg.Name != "" {
+			if j, ok := names[sysCfg.Name]; ok {
+				return nil, errorf(joinPath(path, "name"), "duplicate system name '%s', also used by systems[%d]", sysCfg.Name, j)
+			}
+			names[sysCfg.Name] = i
+			options = append(options, app.WithName(sysCfg.Name))
+		}
+		if sysCfg.Stage != "" {
+			stage, ok := parseStage(sysCfg.Stage)
+			if !ok {
+				return nil, errorf(joinPath(path, "stage"), "unknown stage '%s'", sysCfg.Stage)
+			}
+			options = append(options, app.InStage(stage))
+		}
+		if uiSys, ok := sys.(app.UISystem); ok {
+			a.AddUISystem(uiSys, options...)
+		} else {
+			a.AddSystem(sys.(app.System), options...)
+		}
+	}
+
+	if cfg.Steps > 0 {
+		a.AddSystem(&system.FixedTermination{Steps: cfg.Steps})
+	}
+	return a, nil
+}
+
+// Load reads a [Config] from a file, and creates an app from it.
+// See [Registry.ReadFile] and [Registry.Build].
+func (r *Registry) Load(path string) (*app.App, error) {
+	cfg, err := r.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	a, err := r.Build(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return a, nil
+}
+
+// parseStage parses a stage from its name, case-insensitively.
+func parseStage(name string) (app.Stage, bool) {
+	for stage := app.StagePreUpdate; stage <= app.StageObserve; stage++ {
+		if strings.EqualFold(stage.String(), name) {
+			return stage, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,288 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/config"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+// growthSystem has fields of various types, for testing.
+type growthSystem struct {
+	Rate     float64
+	Count    int
+	Seed     uint64
+	Label    string
+	Active   bool
+	Delay    time.Duration
+	Weights  []float64
+	Limits   map[string]int
+	Nested   *nestedConfig
+	Extra    any
+	Updates  int
+	internal int
+}
+
+type nestedConfig struct {
+	Value int
+}
+
+func (s *growthSystem) Initialize(w *ecs.World) {}
+func (s *growthSystem) Update(w *ecs.World)     { s.Updates++ }
+func (s *growthSystem) Finalize(w *ecs.World)   {}
+
+// viewSystem is a system that is also a UI system.
+type viewSystem struct {
+	Updates   int
+	UIUpdates int
+}
+
+func (s *viewSystem) Initialize(w *ecs.World)   {}
+func (s *viewSystem) Update(w *ecs.World)       { s.Updates++ }
+func (s *viewSystem) Finalize(w *ecs.World)     {}
+func (s *viewSystem) InitializeUI(w *ecs.World) {}
+func (s *viewSystem) UpdateUI(w *ecs.World)     { s.UIUpdates++ }
+func (s *viewSystem) PostUpdateUI(w *ecs.World) {}
+func (s *viewSystem) FinalizeUI(w *ecs.World)   {}
+
+// populationObserver reports a constant value.
+type populationObserver struct {
+	Value float64
+}
+
+func (o *populationObserver) Initialize(w *ecs.World) {}
+func (o *populationObserver) Update(w *ecs.World)     {}
+func (o *populationObserver) Header() []string        { return []string{"population"} }
+func (o *populationObserver) Values(w *ecs.World) []float64 {
+	return []float64{o.Value}
+}
+
+func newRegistry() *config.Registry {
+	registry := config.NewRegistry()
+	config.RegisterSystem[growthSystem](registry, "Growth")
+	config.RegisterObserver[populationObserver](registry, "Population")
+	return registry
+}
+
+const yamlConfig = `
+tps: 100
+fps: 10
+seed: 18446744073709551615
+steps: 50
+systems:
+  - type: Growth
+    name: growth
+    stage: PreUpdate
+    fields:
+      rate: 0.5
+      Count: 3
+      Seed: 12345678901234567
+      Label: test
+      Active: true
+      Delay: 1m30s
+      Weights: [1, 2.5]
+      Limits: {a: 1, b: 2}
+      Nested: {Value: 7}
+      Extra: [1, "x"]
+  - type: Print
+    fields:
+      UpdateInterval: 10
+      Observer:
+        type: Population
+        fields:
+          Value: 42
+`
+
+func TestReadYAML(t *testing.T) {
+	registry := newRegistry()
+	cfg, err := registry.Read(strings.NewReader(yamlConfig), config.YAML)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(18446744073709551615), *cfg.Seed)
+
+	a, err := registry.Build(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, 100.0, a.TPS)
+	assert.Equal(t, 10.0, a.FPS)
+	assert.Equal(t, uint64(18446744073709551615), ecs.GetResource[resource.Rand](&a.World).Seed())
+
+	growth, ok := app.FindSystem[*growthSystem](&a.Systems)
+	assert.True(t, ok)
+	assert.Equal(t, &growthSystem{
+		Rate:    0.5,
+		Count:   3,
+		Seed:    12345678901234567,
+		Label:   "test",
+		Active:  true,
+		Delay:   90 * time.Second,
+		Weights: []float64{1, 2.5},
+		Limits:  map[string]int{"a": 1, "b": 2},
+		Nested:  &nestedConfig{Value: 7},
+		Extra:   []any{1, "x"},
+	}, growth)
+
+	found, ok := a.Find("growth")
+	assert.True(t, ok)
+	assert.Same(t, growth, found)
+
+	print, ok := app.FindSystem[*reporter.Print](&a.Systems)
+	assert.True(t, ok)
+	assert.Equal(t, 10, print.UpdateInterval)
+	assert.Equal(t, &populationObserver{Value: 42}, print.Observer)
+
+	_, ok = app.FindSystem[*system.FixedTermination](&a.Systems)
+	assert.True(t, ok)
+}
+
+func TestReadJSON(t *testing.T) {
+	registry := newRegistry()
+	cfg, err := registry.Read(strings.NewReader(`{
+		"Seed": 18446744073709551615,
+		"Steps": 20,
+		"Systems": [{"Type": "Growth", "Fields": {"Count": 2, "Delay": 1000}}]
+	}`), config.JSON)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(18446744073709551615), *cfg.Seed)
+
+	a, err := registry.Build(cfg)
+	assert.Nil(t, err)
+	a.TPS = 0
+	a.Run()
+
+	growth, _ := app.FindSystem[*growthSystem](&a.Systems)
+	assert.Equal(t, 2, growth.Count)
+	assert.Equal(t, time.Microsecond, growth.Delay)
+	assert.Equal(t, 20, growth.Updates)
+}
+
+func TestConfigErrors(t *testing.T) {
+	registry := newRegistry()
+	tests := []struct {
+		config string
+		err    string
+	}{
+		{`tps: fast`, "tps: expected a number, got string"},
+		{`unknown: 1`, "unknown: unknown field of config.Config"},
+		{`systems: [{type: Missing}]`, "systems[0].type: unknown system type 'Missing'"},
+		{`systems: [{type: Growth, fields: {Count: 1.5}}]`, "systems[0].fields.Count: expected an integer in the range of int, got 1.5"},
+		{`systems: [{type: Growth, fields: {Seed: -1}}]`, "systems[0].fields.Seed: expected a non-negative integer in the range of uint64, got -1"},
+		{`systems: [{type: Growth, fields: {internal: 1}}]`, "systems[0].fields.internal: unknown field of config_test.growthSystem"},
+		{`systems: [{type: Growth, fields: {Weights: [1, a]}}]`, "systems[0].fields.Weights[1]: expected a number, got string"},
+		{`systems: [{type: Growth, fields: {Delay: soon}}]`, "systems[0].fields.Delay: invalid duration 'soon'"},
+		{`systems: [{type: Growth, fields: {Nested: {Value: x}}}]`, "systems[0].fields.Nested.Value: expected an integer in the range of int, got x"},
+		{`systems: [{type: Growth, stage: Later}]`, "systems[0].stage: unknown stage 'Later'"},
+		{`systems: [{type: Growth, name: a}, {type: Growth, name: a}]`, "systems[1].name: duplicate system name 'a', also used by systems[0]"},
+		{`systems: [{type: Print, fields: {Observer: {type: Unknown}}}]`, "systems[0].fields.Observer.type: unknown observer type 'Unknown'"},
+		{`systems: [{type: Print, fields: {Observer: {fields: {}}}}]`, "systems[0].fields.Observer.type: missing observer type"},
+		{`systems: [{type: Print, fields: {Observer: {type: Population, other: 1}}}]`, "systems[0].fields.Observer.other: unknown key, expected 'type' or 'fields'"},
+	}
+
+	for _, test := range tests {
+		cfg, err := registry.Read(strings.NewReader(test.config), config.YAML)
+		if err == nil {
+			_, err = registry.Build(cfg)
+		}
+		assert.EqualError(t, err, test.err, test.config)
+		var cfgErr *config.Error
+		assert.True(t, errors.As(err, &cfgErr), test.config)
+	}
+
+	config.RegisterObserver[growthSystem](registry, "NotAnObserver")
+	cfg, err := registry.Read(strings.NewReader(`systems: [{type: Print, fields: {Observer: {type: NotAnObserver}}}]`), config.YAML)
+	assert.Nil(t, err)
+	_, err = registry.Build(cfg)
+	assert.EqualError(t, err, "systems[0].fields.Observer.type: observer type *config_test.growthSystem does not implement observer.Row")
+
+	_, err = registry.Read(strings.NewReader(`{`), config.JSON)
+	assert.ErrorContains(t, err, "reading JSON")
+
+	assert.Panics(t, func() { config.RegisterSystem[growthSystem](registry, "Growth") })
+	assert.Panics(t, func() { config.RegisterObserver[populationObserver](registry, "Population") })
+}
+
+func TestBuildUISystem(t *testing.T) {
+	registry := newRegistry()
+	config.RegisterSystem[viewSystem](registry, "View")
+
+	cfg, err := registry.Read(strings.NewReader(`{"steps": 10, "systems": [{"type": "View", "name": "view"}]}`), config.JSON)
+	assert.Nil(t, err)
+	a, err := registry.Build(cfg)
+	assert.Nil(t, err)
+
+	view, ok := app.FindSystem[*viewSystem](&a.Systems)
+	assert.True(t, ok)
+	assert.Contains(t, a.UISystems(), app.UISystem(view))
+
+	a.Run()
+	assert.Equal(t, 10, view.Updates)
+	assert.Greater(t, view.UIUpdates, 0)
+}
+
+func TestLoad(t *testing.T) {
+	registry := newRegistry()
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "model.yml")
+	assert.Nil(t, os.WriteFile(file, []byte(yamlConfig), 0o644))
+	a, err := registry.Load(file)
+	assert.Nil(t, err)
+	assert.Equal(t, 100.0, a.TPS)
+
+	file = filepath.Join(dir, "model.json")
+	assert.Nil(t, os.WriteFile(file, []byte(`{"systems": [{"type": "Missing"}]}`), 0o644))
+	_, err = registry.Load(file)
+	assert.EqualError(t, err, file+": systems[0].type: unknown system type 'Missing'")
+
+	_, err = registry.Load(filepath.Join(dir, "model.toml"))
+	assert.ErrorContains(t, err, "unknown config file extension")
+
+	_, err = registry.Load(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+
+	assert.Contains(t, registry.Systems(), "CSV")
+	assert.Contains(t, registry.Observers(), "Population")
+}
+
+func ExampleRegistry() {
+	registry := config.NewRegistry()
+	config.RegisterSystem[growthSystem](registry, "Growth")
+	config.RegisterObserver[populationObserver](registry, "Population")
+
+	cfg, err := registry.Read(strings.NewReader(`
+seed: 42
+steps: 100
+systems:
+  - type: Growth
+    fields:
+      Rate: 0.1
+  - type: Print
+    fields:
+      UpdateInterval: 50
+      Observer:
+        type: Population
+        fields:
+          Value: 10
+`), config.YAML)
+	if err != nil {
+		panic(err)
+	}
+
+	myApp, err := registry.Build(cfg)
+	if err != nil {
+		panic(err)
+	}
+	myApp.Run()
+	// Output: [population]
+	// [10]
+	// [population]
+	// [10]
+}
@@ -0,0 +1,26 @@
+// Package config provides assembling an [github.com/mlange-42/ark-tools/app.App] from declarative
+// JSON or YAML configuration.
+//
+// System and observer types are registered by name in a [Registry].
+// A [Config] lists the systems of a model with their field values, as well as the app's settings
+// like TPS, FPS, the random seed and a fixed number of steps for termination.
+// Fields of systems that are observers, like the Observer of a [github.com/mlange-42/ark-tools/reporter.CSV],
+// are configured like systems, with a type and fields.
+//
+// Example in YAML format:
+//
+//	seed: 42
+//	steps: 1000
+//	systems:
+//	  - type: Growth
+//	    fields:
+//	      Rate: 0.1
+//	  - type: CSV
+//	    fields:
+//	      File: out/population.csv
+//	      UpdateInterval: 10
+//	      Observer:
+//	        type: Population
+//
+// Errors in the configuration name the path of the offending entry, like "systems[1].fields.Observer.type".
+package config
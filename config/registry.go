@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark-tools/system"
+)
+
+// Registry of system and observer types by name.
+//
+// Use [RegisterSystem] and [RegisterObserver] to register types.
+type Registry struct {
+	systems   map[string]func() any
+	observers map[string]func() any
+}
+
+// NewRegistry creates a new [Registry], with the systems of packages
+// [github.com/mlange-42/ark-tools/system] and [github.com/mlange-42/ark-tools/reporter] registered,
+// as far as they can be configured declaratively.
+// Also registers [app.ProfileObserver] as an observer.
+//
+// Built-in types are registered under their type name without package, like "CSV" or "FixedTermination".
+func NewRegistry() *Registry {
+	r := &Registry{
+		systems:   map[string]func() any{},
+		observers: map[string]func() any{},
+	}
+	RegisterSystem[system.FixedTermination](r, "FixedTermination")
+	RegisterSystem[system.WallClockTermination](r, "WallClockTermination")
+	RegisterSystem[system.ConvergenceTermination](r, "ConvergenceTermination")
+	RegisterSystem[system.EventDispatcher](r, "EventDispatcher")
+	RegisterSystem[system.PerfTimer](r, "PerfTimer")
+	RegisterSystem[reporter.CSV](r, "CSV")
+	RegisterSystem[reporter.SnapshotCSV](r, "SnapshotCSV")
+	RegisterSystem[reporter.Print](r, "Print")
+	RegisterObserver[app.ProfileObserver](r, "ProfileObserver")
+	return r
+}
+
+// RegisterSystem registers a system type under the given name.
+// The system is created as a pointer to a zero value of T, with fields set from the configuration.
+// Systems that also implement [app.UISystem] are added via [app.App.AddUISystem].
+//
+// Panics if the name is already registered.
+func RegisterSystem[T any, P interface {
+	*T
+	app.System
+}](r *Registry, name string) {
+	if _, ok := r.systems[name]; ok {
+		panic(fmt.Sprintf("system type '%s' is already registered", name))
+	}
+	r.systems[name] = func() any { return P(new(T)) }
+}
+
+// RegisterObserver registers an observer type under the given name.
+// The observer is created as a pointer to a zero value of T, with fields set from the configuration.
+//
+// Observers can be of any type. Their type is checked when they are assigned to a field of a system.
+// Panics if the name is already registered.
+func RegisterObserver[T any](r *Registry, name string) {
+	if _, ok := r.observers[name]; ok {
+		panic(fmt.Sprintf("observer type '%s' is already registered", name))
+	}
+	r.observers[name] = func() any { return new(T) }
+}
+
+// Systems returns the names of all registered system types, sorted.
+func (r *Registry) Systems() []string {
+	return sortedKeys(r.systems)
+}
+
+// Observers returns the names of all registered observer types, sorted.
+func (r *Registry) Observers() []string {
+	return sortedKeys(r.observers)
+}
+
+func sortedKeys(m map[string]func() any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
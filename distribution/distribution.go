@@ -0,0 +1,149 @@
+package distribution
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Normal draws from a normal distribution with the given mean and standard deviation.
+func Normal(rng *rand.Rand, mean, sd float64) float64 {
+	return mean + sd*rng.NormFloat64()
+}
+
+// Exponential draws from an exponential distribution with the given rate.
+//
+// Panics if the rate is not positive.
+func Exponential(rng *rand.Rand, rate float64) float64 {
+	if rate <= 0 {
+		panic(fmt.Sprintf("rate of exponential distribution must be positive, got %f", rate))
+	}
+	return rng.ExpFloat64() / rate
+}
+
+// Poisson draws from a Poisson distribution with the given mean.
+//
+// Panics if the mean is negative.
+func Poisson(rng *rand.Rand, mean float64) int {
+	if mean < 0 {
+		panic(fmt.Sprintf("mean of Poisson distribution must not be negative, got %f", mean))
+	}
+	if mean < 30 {
+		return poissonKnuth(rng, mean)
+	}
+	return poissonPTRS(rng, mean)
+}
+
+// poissonKnuth draws from a Poisson distribution by multiplication of uniform numbers.
+// Efficient for small means.
+func poissonKnuth(rng *rand.Rand, mean float64) int {
+	limit := math.Exp(-mean)
+	k := 0
+	p := rng.Float64()
+	for p > limit {
+		k++
+		p *= rng.Float64()
+	}
+	return k
+}
+
+// poissonPTRS draws from a Poisson distribution using the transformed rejection method
+// of Hörmann (1993). Efficient for large means.
+func poissonPTRS(rng *rand.Rand, mean float64) int {
+	slam := math.Sqrt(mean)
+	logLam := math.Log(mean)
+	b := 0.931 + 2.53*slam
+	a := -0.059 + 0.02483*b
+	invAlpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+
+	for {
+		u := rng.Float64() - 0.5
+		v := rng.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + mean + 0.43)
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		lg, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invAlpha)-math.Log(a/(us*us)+b) <= -mean+k*logLam-lg {
+			return int(k)
+		}
+	}
+}
+
+// Binomial draws from a binomial distribution with n trials and success probability p.
+//
+// Panics if n is negative or p is not in [0, 1].
+func Binomial(rng *rand.Rand, n int, p float64) int {
+	if n < 0 {
+		panic(fmt.Sprintf("number of trials of binomial distribution must not be negative, got %d", n))
+	}
+	if p < 0 || p > 1 {
+		panic(fmt.Sprintf("probability of binomial distribution must be in [0, 1], got %f", p))
+	}
+	// Splitting via order statistics of the beta distribution, see Knuth, TAOCP Vol. 2, 3.4.1.
+	count := 0
+	for n > 30 {
+		a := 1 + n/2
+		b := n + 1 - a
+		x := Beta(rng, float64(a), float64(b))
+		if x >= p {
+			n = a - 1
+			p /= x
+		} else {
+			count += a
+			n = b - 1
+			p = (p - x) / (1 - x)
+		}
+	}
+	for range n {
+		if rng.Float64() < p {
+			count++
+		}
+	}
+	return count
+}
+
+// Gamma draws from a gamma distribution with the given shape and scale.
+//
+// Panics if shape or scale are not positive.
+func Gamma(rng *rand.Rand, shape, scale float64) float64 {
+	if shape <= 0 || scale <= 0 {
+		panic(fmt.Sprintf("shape and scale of gamma distribution must be positive, got %f and %f", shape, scale))
+	}
+	if shape < 1 {
+		// Boosting, see Marsaglia & Tsang (2000).
+		return Gamma(rng, shape+1, scale) * math.Pow(rng.Float64(), 1/shape)
+	}
+	// Marsaglia & Tsang (2000).
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		var x, v float64
+		for v <= 0 {
+			x = rng.NormFloat64()
+			v = 1 + c*x
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v * scale
+		}
+	}
+}
+
+// Beta draws from a beta distribution with the shape parameters alpha and beta.
+//
+// Panics if alpha or beta are not positive.
+func Beta(rng *rand.Rand, alpha, beta float64) float64 {
+	if alpha <= 0 || beta <= 0 {
+		panic(fmt.Sprintf("parameters of beta distribution must be positive, got %f and %f", alpha, beta))
+	}
+	x := Gamma(rng, alpha, 1)
+	y := Gamma(rng, beta, 1)
+	return x / (x + y)
+}
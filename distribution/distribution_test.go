@@ -0,0 +1,113 @@
+package distribution_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/distribution"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+const samples = 100_000
+
+func meanVar[T int | float64](fn func() T) (float64, float64) {
+	sum, sumSq := 0.0, 0.0
+	for range samples {
+		v := float64(fn())
+		sum += v
+		sumSq += v * v
+	}
+	mean := sum / samples
+	return mean, sumSq/samples - mean*mean
+}
+
+func TestNormal(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	mean, variance := meanVar(func() float64 { return distribution.Normal(rng, 5, 2) })
+	assert.InDelta(t, 5, mean, 0.05)
+	assert.InDelta(t, 4, variance, 0.1)
+}
+
+func TestExponential(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	mean, variance := meanVar(func() float64 { return distribution.Exponential(rng, 2) })
+	assert.InDelta(t, 0.5, mean, 0.01)
+	assert.InDelta(t, 0.25, variance, 0.01)
+	assert.Panics(t, func() { distribution.Exponential(rng, 0) })
+}
+
+func TestPoisson(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	for _, lambda := range []float64{0, 0.5, 4, 29, 30, 100, 1000} {
+		mean, variance := meanVar(func() int { return distribution.Poisson(rng, lambda) })
+		assert.InDelta(t, lambda, mean, 0.02*lambda+0.01, "lambda=%f", lambda)
+		assert.InDelta(t, lambda, variance, 0.05*lambda+0.01, "lambda=%f", lambda)
+	}
+	assert.Panics(t, func() { distribution.Poisson(rng, -1) })
+}
+
+func TestBinomial(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	for _, c := range []struct {
+		n int
+		p float64
+	}{{0, 0.5}, {10, 0}, {10, 1}, {10, 0.3}, {100, 0.5}, {1000, 0.01}, {10000, 0.9}} {
+		mean, variance := meanVar(func() int { return distribution.Binomial(rng, c.n, c.p) })
+		expMean, expVar := float64(c.n)*c.p, float64(c.n)*c.p*(1-c.p)
+		assert.InDelta(t, expMean, mean, 0.01*expMean+0.01, "n=%d, p=%f", c.n, c.p)
+		assert.InDelta(t, expVar, variance, 0.05*expVar+0.01, "n=%d, p=%f", c.n, c.p)
+	}
+	assert.Panics(t, func() { distribution.Binomial(rng, -1, 0.5) })
+	assert.Panics(t, func() { distribution.Binomial(rng, 10, 1.5) })
+}
+
+func TestGamma(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	for _, shape := range []float64{0.5, 1, 3} {
+		mean, variance := meanVar(func() float64 { return distribution.Gamma(rng, shape, 2) })
+		assert.InDelta(t, shape*2, mean, 0.02*shape*2, "shape=%f", shape)
+		assert.InDelta(t, shape*4, variance, 0.05*shape*4, "shape=%f", shape)
+	}
+	assert.Panics(t, func() { distribution.Gamma(rng, 0, 1) })
+}
+
+func TestBeta(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	a, b := 2.0, 5.0
+	mean, variance := meanVar(func() float64 { return distribution.Beta(rng, a, b) })
+	assert.InDelta(t, a/(a+b), mean, 0.005)
+	assert.InDelta(t, a*b/((a+b)*(a+b)*(a+b+1)), variance, 0.001)
+	assert.Panics(t, func() { distribution.Beta(rng, 0, 1) })
+}
+
+func TestDeterministic(t *testing.T) {
+	draw := func() []float64 {
+		rng := rand.New(rand.NewPCG(0, 42))
+		return []float64{
+			distribution.Normal(rng, 0, 1),
+			float64(distribution.Poisson(rng, 50)),
+			float64(distribution.Binomial(rng, 100, 0.2)),
+			distribution.Gamma(rng, 0.5, 1),
+		}
+	}
+	assert.Equal(t, draw(), draw())
+	assert.False(t, math.IsNaN(draw()[3]))
+}
+
+func ExampleNormal() {
+	myApp := app.New(1024).Seed(42)
+
+	// Typically in the Initialize method of a system.
+	src := ecs.GetResource[resource.Rand](&myApp.World)
+	rng := rand.New(src.Stream("growth"))
+
+	// Typically in the Update method of a system.
+	growth := distribution.Normal(rng, 1.0, 0.1)
+	fmt.Println(growth > 0)
+	// Output: true
+}
@@ -0,0 +1,6 @@
+// Package distribution provides sampling from probability distributions, and random sampling helpers.
+//
+// All functions draw from a [math/rand/v2.Rand], which is typically created from the app's
+// [github.com/mlange-42/ark-tools/resource.Rand] resource or one of its streams.
+// Thus, results are deterministic given the app seed.
+package distribution
@@ -0,0 +1,150 @@
+package distribution
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Weighted draws an index from the given weights, with probabilities proportional to the weights.
+//
+// Panics if there are no weights, if any weight is negative, or if all weights are zero.
+func Weighted(rng *rand.Rand, weights []float64) int {
+	sum := 0.0
+	for _, w := range weights {
+		if w < 0 {
+			panic(fmt.Sprintf("weights must not be negative, got %f", w))
+		}
+		sum += w
+	}
+	if sum <= 0 {
+		panic("weights must contain at least one positive value")
+	}
+	r := rng.Float64() * sum
+	last := 0
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		if r < w {
+			return i
+		}
+		r -= w
+		last = i
+	}
+	return last
+}
+
+// WeightedSample draws k distinct indices from the given weights, without replacement.
+// In each draw, probabilities are proportional to the weights of the remaining indices.
+//
+// Indices with zero weight are never drawn, so fewer than k indices may be returned.
+// Uses the algorithm of Efraimidis & Spirakis (2006).
+//
+// Panics if k is negative or any weight is negative.
+func WeightedSample(rng *rand.Rand, weights []float64, k int) []int {
+	if k < 0 {
+		panic(fmt.Sprintf("sample size must not be negative, got %d", k))
+	}
+	type entry struct {
+		index int
+		key   float64
+	}
+	entries := make([]entry, 0, len(weights))
+	for i, w := range weights {
+		if w < 0 {
+			panic(fmt.Sprintf("weights must not be negative, got %f", w))
+		}
+		if w == 0 {
+			continue
+		}
+		entries = append(entries, entry{i, math.Log(rng.Float64()) / w})
+	}
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return cmp.Compare(b.key, a.key)
+	})
+
+	result := make([]int, min(k, len(entries)))
+	for i := range result {
+		result[i] = entries[i].index
+	}
+	return result
+}
+
+// Shuffle shuffles a slice, e.g. of entities, in place.
+func Shuffle[T any](rng *rand.Rand, s []T) {
+	rng.Shuffle(len(s), func(i, j int) {
+		s[i], s[j] = s[j], s[i]
+	})
+}
+
+// Reservoir draws a uniform random sample of fixed size from a sequence of unknown length,
+// like the results of a query.
+//
+// Uses reservoir sampling (Algorithm R).
+type Reservoir[T any] struct {
+	rng    *rand.Rand
+	size   int
+	seen   int
+	sample []T
+}
+
+// NewReservoir creates a new [Reservoir] for a sample of the given size.
+//
+// Panics if the size is negative.
+func NewReservoir[T any](rng *rand.Rand, size int) *Reservoir[T] {
+	if size < 0 {
+		panic(fmt.Sprintf("sample size must not be negative, got %d", size))
+	}
+	return &Reservoir[T]{
+		rng:    rng,
+		size:   size,
+		sample: make([]T, 0, size),
+	}
+}
+
+// Add an item of the sequence.
+func (r *Reservoir[T]) Add(item T) {
+	r.seen++
+	if len(r.sample) < r.size {
+		r.sample = append(r.sample, item)
+		return
+	}
+	if idx := r.rng.IntN(r.seen); idx < r.size {
+		r.sample[idx] = item
+	}
+}
+
+// Sample returns the current sample.
+// Contains fewer items than the sample size if fewer items were added.
+func (r *Reservoir[T]) Sample() []T {
+	return r.sample
+}
+
+// Reset the reservoir for drawing a new sample.
+func (r *Reservoir[T]) Reset() {
+	r.seen = 0
+	r.sample = r.sample[:0]
+}
+
+// EntityQuery is the common interface of Ark queries for iterating entities.
+type EntityQuery interface {
+	Next() bool
+	Entity() ecs.Entity
+}
+
+// SampleEntities draws a uniform random sample of k entities from a query, without replacement.
+// Iterates the query completely, so it must not be closed afterwards.
+//
+// Panics if k is negative.
+func SampleEntities(rng *rand.Rand, query EntityQuery, k int) []ecs.Entity {
+	reservoir := NewReservoir[ecs.Entity](rng, k)
+	for query.Next() {
+		reservoir.Add(query.Entity())
+	}
+	return reservoir.Sample()
+}
@@ -0,0 +1,116 @@
+package distribution_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/distribution"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeighted(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	weights := []float64{1, 0, 3}
+	counts := make([]int, len(weights))
+	for range samples {
+		counts[distribution.Weighted(rng, weights)]++
+	}
+	assert.Equal(t, 0, counts[1])
+	assert.InDelta(t, 0.25, float64(counts[0])/samples, 0.01)
+
+	assert.Panics(t, func() { distribution.Weighted(rng, nil) })
+	assert.Panics(t, func() { distribution.Weighted(rng, []float64{0, 0}) })
+	assert.Panics(t, func() { distribution.Weighted(rng, []float64{1, -1}) })
+}
+
+func TestWeightedSample(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	weights := []float64{1, 0, 2, 1}
+
+	firsts := make([]int, len(weights))
+	for range samples {
+		sample := distribution.WeightedSample(rng, weights, 2)
+		assert.Equal(t, 2, len(sample))
+		assert.NotEqual(t, sample[0], sample[1])
+		assert.NotContains(t, sample, 1)
+		firsts[sample[0]]++
+	}
+	assert.InDelta(t, 0.5, float64(firsts[2])/samples, 0.01)
+
+	sample := distribution.WeightedSample(rng, weights, 10)
+	slices.Sort(sample)
+	assert.Equal(t, []int{0, 2, 3}, sample)
+
+	assert.Panics(t, func() { distribution.WeightedSample(rng, weights, -1) })
+	assert.Panics(t, func() { distribution.WeightedSample(rng, []float64{-1}, 1) })
+}
+
+func TestShuffle(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	distribution.Shuffle(rng, s)
+	assert.NotEqual(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, s)
+	slices.Sort(s)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, s)
+}
+
+func TestReservoir(t *testing.T) {
+	rng := rand.New(rand.NewPCG(0, 1))
+	reservoir := distribution.NewReservoir[int](rng, 2)
+
+	counts := make([]int, 10)
+	for range samples {
+		reservoir.Reset()
+		for i := range 10 {
+			reservoir.Add(i)
+		}
+		sample := reservoir.Sample()
+		assert.Equal(t, 2, len(sample))
+		counts[sample[0]]++
+		counts[sample[1]]++
+	}
+	for _, c := range counts {
+		assert.InDelta(t, 0.2, float64(c)/samples, 0.01)
+	}
+
+	reservoir.Reset()
+	reservoir.Add(1)
+	assert.Equal(t, []int{1}, reservoir.Sample())
+
+	assert.Panics(t, func() { distribution.NewReservoir[int](rng, -1) })
+}
+
+type position struct {
+	X, Y float64
+}
+
+func TestSampleEntities(t *testing.T) {
+	world := ecs.NewWorld()
+	ecs.NewMap1[position](&world).NewBatchFn(100, nil)
+
+	rng := rand.New(rand.NewPCG(0, 1))
+	filter := ecs.NewFilter1[position](&world)
+	query := filter.Query()
+	sample := distribution.SampleEntities(rng, &query, 10)
+	assert.Equal(t, 10, len(sample))
+	for _, e := range sample {
+		assert.True(t, world.Alive(e))
+	}
+	assert.False(t, world.IsLocked())
+}
+
+func ExampleSampleEntities() {
+	world := ecs.NewWorld()
+	ecs.NewMap1[position](&world).NewBatchFn(100, nil)
+
+	rng := rand.New(rand.NewPCG(0, 42))
+	filter := ecs.NewFilter1[position](&world)
+	query := filter.Query()
+
+	sample := distribution.SampleEntities(rng, &query, 5)
+	fmt.Println(len(sample))
+	// Output: 5
+}
@@ -7,4 +7,9 @@
 //   - Reporter systems for data handling -- [github.com/mlange-42/ark-tools/reporter]
 //   - Observers for data extraction -- [github.com/mlange-42/ark-tools/observer]
 //   - Commonly used resources -- [github.com/mlange-42/ark-tools/resource]
+//   - Parallel runner for replicated simulations -- [github.com/mlange-42/ark-tools/batch]
+//   - Parameter sweeps over simulation models -- [github.com/mlange-42/ark-tools/experiment]
+//   - Probability distributions and random sampling -- [github.com/mlange-42/ark-tools/distribution]
+//   - Declarative model configuration from JSON or YAML -- [github.com/mlange-42/ark-tools/config]
+//   - Command line interface with run, batch and sweep commands -- [github.com/mlange-42/ark-tools/cli]
 package arktools
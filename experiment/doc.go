@@ -0,0 +1,6 @@
+// Package experiment provides parameter sweeps over simulation models.
+//
+// An [Experiment] runs every combination of parameter values, each with a number of replicates.
+// Parameter values are injected into the world of each run as a [Parameters] resource,
+// and data from [github.com/mlange-42/ark-tools/observer.Row] observers is collected into a combined [Table].
+package experiment
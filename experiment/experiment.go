@@ -0,0 +1,159 @@
+package experiment
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/batch"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Experiment runs a full-factorial parameter sweep.
+//
+// Every combination of parameter values is run with the given number of replicates.
+// Runs are executed in parallel, using [batch.Batch].
+// Before each run, the run's parameter values are added to the world as a [Parameters] resource.
+// If the Factory already added a Parameters resource, its values are overwritten.
+//
+// If an Observer is given, its data is collected for each run,
+// and returned as a single [Table], tagged with the run's parameter values.
+type Experiment struct {
+	Parameters     []Parameter                                             // Parameters to sweep over. The last parameter varies fastest.
+	Replicates     int                                                     // Number of replicates per parameter combination. Default 1.
+	Workers        int                                                     // Number of worker goroutines. Values <= 0 use the number of CPUs.
+	Seed           uint64                                                  // Master seed, from which the seeds of runs are derived.
+	Factory        func(run int, params *Parameters, seed uint64) *app.App // Creates the app for a run, see [Experiment.Combination]. Should seed the app with the given seed.
+	Observer       func() observer.Row                                     // Creates an observer for a run. Optional.
+	UpdateInterval int                                                     // Interval for collecting observer data, in ticks. Default 1.
+	Final          bool                                                    // Whether to collect observer data only at the end of each run.
+}
+
+// Runs returns the total number of runs, i.e. the number of parameter combinations times the number of replicates.
+func (e *Experiment) Runs() int {
+	runs := e.replicates()
+	for _, p := range e.Parameters {
+		runs *= len(p.Values)
+	}
+	return runs
+}
+
+// Combination returns the parameter values of the given run, and the run's replicate index.
+func (e *Experiment) Combination(run int) (*Parameters, int) {
+	replicate := run % e.replicates()
+	idx := run / e.replicates()
+
+	params := Parameters{
+		Names:  make([]string, len(e.Parameters)),
+		Values: make([]float64, len(e.Parameters)),
+	}
+	for i := len(e.Parameters) - 1; i >= 0; i-- {
+		p := &e.Parameters[i]
+		params.Names[i] = p.Name
+		params.Values[i] = p.Values[idx%len(p.Values)]
+		idx /= len(p.Values)
+	}
+	return &params, replicate
+}
+
+// Run the experiment, and return the collected data.
+//
+// Returns an error that joins the errors of all failed runs.
+// Data of successful runs is returned also in case of errors.
+func (e *Experiment) Run() (*Table, error) {
+	return e.RunContext(context.Background())
+}
+
+// RunContext runs the experiment like [Experiment.Run], but stops when the context is cancelled.
+func (e *Experiment) RunContext(ctx context.Context) (*Table, error) {
+	if e.Factory == nil {
+		panic("experiment requires a Factory")
+	}
+	var header []string
+	var headerOnce sync.Once
+
+	b := batch.Batch[[][]float64]{
+		Runs:    e.Runs(),
+		Workers: e.Workers,
+		Seed:    e.Seed,
+		Factory: func(run int, seed uint64) *app.App {
+			params, _ := e.Combination(run)
+			a := e.Factory(run, params, seed)
+			res := ecs.NewResource[Parameters](&a.World)
+			if res.Has() {
+				*res.Get() = *params
+			} else {
+				res.Add(params)
+			}
+			return a
+		},
+	}
+	b.Collect = func(run int, a *app.App) ([][]float64, error) {
+		return collected(a), nil
+	}
+	if e.Observer != nil {
+		factory := b.Factory
+		b.Factory = func(run int, seed uint64) *app.App {
+			a := factory(run, seed)
+			params, replicate := e.Combination(run)
+			rows := &collector{}
+			ecs.AddResource(&a.World, rows)
+			a.AddSystem(&reporter.RowCallback{
+				Observer:       e.Observer(),
+				UpdateInterval: e.UpdateInterval,
+				Final:          e.Final,
+				HeaderCallback: func(h []string) {
+					headerOnce.Do(func() { header = slices.Clone(h) })
+				},
+				Callback: func(step int, row []float64) {
+					values := make([]float64, 0, len(params.Values)+3+len(row))
+					values = append(values, float64(run), float64(replicate))
+					values = append(values, params.Values...)
+					values = append(values, float64(step))
+					rows.Rows = append(rows.Rows, append(values, row...))
+				},
+			}, app.InStage(app.StageObserve), app.WithPriority(-1))
+			return a
+		}
+	}
+
+	results, err := b.RunContext(ctx)
+
+	table := Table{}
+	if header != nil {
+		table.Header = append(table.Header, "run", "replicate")
+		for _, p := range e.Parameters {
+			table.Header = append(table.Header, p.Name)
+		}
+		table.Header = append(table.Header, "t")
+		table.Header = append(table.Header, header...)
+	}
+	for _, res := range results {
+		table.Rows = append(table.Rows, res.Value...)
+	}
+	return &table, err
+}
+
+func (e *Experiment) replicates() int {
+	if e.Replicates <= 0 {
+		return 1
+	}
+	return e.Replicates
+}
+
+// collector is a resource for collecting observer rows of a run.
+type collector struct {
+	Rows [][]float64
+}
+
+// collected returns the rows collected in an app, if any.
+func collected(a *app.App) [][]float64 {
+	res := ecs.NewResource[collector](&a.World)
+	if !res.Has() {
+		return nil
+	}
+	return res.Get().Rows
+}
@@ -0,0 +1,232 @@
+package experiment_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/experiment"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperimentCombination(t *testing.T) {
+	e := experiment.Experiment{
+		Parameters: []experiment.Parameter{
+			experiment.Values("a", 1, 2),
+			experiment.Values("b", 10, 20, 30),
+		},
+		Replicates: 2,
+	}
+	assert.Equal(t, 12, e.Runs())
+
+	params, rep := e.Combination(0)
+	assert.Equal(t, []float64{1, 10}, params.Values)
+	assert.Equal(t, 0, rep)
+
+	params, rep = e.Combination(3)
+	assert.Equal(t, []float64{1, 20}, params.Values)
+	assert.Equal(t, 1, rep)
+
+	params, rep = e.Combination(11)
+	assert.Equal(t, []string{"a", "b"}, params.Names)
+	assert.Equal(t, []float64{2, 30}, params.Values)
+	assert.Equal(t, 1, rep)
+}
+
+func TestExperiment(t *testing.T) {
+	e := experiment.Experiment{
+		Parameters: []experiment.Parameter{
+			experiment.Values("a", 1, 2),
+			experiment.Linear("b", 0, 1, 3),
+		},
+		Replicates: 2,
+		Workers:    3,
+		Seed:       42,
+		Factory: func(run int, params *experiment.Parameters, seed uint64) *app.App {
+			a := app.New(1024).Seed(seed)
+			a.AddSystem(&system.FixedTermination{Steps: int64(params.Get("a")) * 5})
+			return a
+		},
+		Observer:       func() observer.Row { return &paramsObserver{} },
+		UpdateInterval: 5,
+	}
+
+	table, err := e.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"run", "replicate", "a", "b", "t", "sum", "rand"}, table.Header)
+
+	// 6 runs with 1 row, 6 runs with 2 rows
+	assert.Equal(t, 18, len(table.Rows))
+	for _, row := range table.Rows {
+		assert.Equal(t, 7, len(row))
+		assert.Equal(t, row[2]+row[3], row[5])
+	}
+
+	table2, err := e.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, table.Rows, table2.Rows)
+
+	e.Final = true
+	table, err = e.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(table.Rows))
+}
+
+func TestExperimentNoObserver(t *testing.T) {
+	runs := 0
+	e := experiment.Experiment{
+		Parameters: []experiment.Parameter{experiment.Values("a", 1, 2)},
+		Workers:    1,
+		Factory: func(run int, params *experiment.Parameters, seed uint64) *app.App {
+			runs++
+			a := app.New(1024).Seed(seed)
+			a.AddSystem(&system.FixedTermination{Steps: 10})
+			return a
+		},
+	}
+	table, err := e.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, runs)
+	assert.Empty(t, table.Header)
+	assert.Empty(t, table.Rows)
+
+	e.Factory = nil
+	assert.Panics(t, func() { _, _ = e.Run() })
+}
+
+func TestExperimentFactoryParameters(t *testing.T) {
+	e := experiment.Experiment{
+		Parameters: []experiment.Parameter{experiment.Values("a", 1, 2)},
+		Workers:    1,
+		Factory: func(run int, params *experiment.Parameters, seed uint64) *app.App {
+			a := app.New(1024).Seed(seed)
+			ecs.AddResource(&a.World, &experiment.Parameters{Names: []string{"a"}, Values: []float64{-1}})
+			a.AddSystem(&system.FixedTermination{Steps: 1})
+			return a
+		},
+		Observer: func() observer.Row { return &sumObserver{} },
+		Final:    true,
+	}
+	table, err := e.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(table.Rows))
+	assert.Equal(t, table.Rows[0][2], table.Rows[0][4])
+	assert.Equal(t, table.Rows[1][2], table.Rows[1][4])
+}
+
+func TestExperimentContext(t *testing.T) {
+	e := experiment.Experiment{
+		Parameters: []experiment.Parameter{experiment.Values("a", 1, 2)},
+		Factory: func(run int, params *experiment.Parameters, seed uint64) *app.App {
+			a := app.New(1024).Seed(seed)
+			a.AddSystem(&system.FixedTermination{Steps: 10})
+			return a
+		},
+		Observer: func() observer.Row { return &paramsObserver{} },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := e.RunContext(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestTableWriteCSV(t *testing.T) {
+	table := experiment.Table{
+		Header: []string{"a", "b"},
+		Rows:   [][]float64{{1, 2.5}, {3, 4}},
+	}
+	buf := bytes.Buffer{}
+	assert.Nil(t, table.WriteCSV(&buf, ""))
+	assert.Equal(t, "a,b\n1,2.5\n3,4\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, table.WriteCSV(&buf, ";"))
+	assert.Equal(t, "a;b\n1;2.5\n3;4\n", buf.String())
+}
+
+func ExampleExperiment() {
+	e := experiment.Experiment{
+		Parameters: []experiment.Parameter{
+			experiment.Values("a", 1, 2),
+			experiment.Values("b", 10, 20),
+		},
+		Replicates: 2,
+		Seed:       42,
+		Factory: func(run int, params *experiment.Parameters, seed uint64) *app.App {
+			myApp := app.New(1024).Seed(seed)
+			myApp.AddSystem(&system.FixedTermination{Steps: 100})
+			return myApp
+		},
+		Observer: func() observer.Row { return &sumObserver{} },
+		Final:    true,
+	}
+
+	table, err := e.Run()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(table.Rows))
+	if err := table.WriteCSV(os.Stdout, ","); err != nil {
+		panic(err)
+	}
+	// Output: 8
+	// run,replicate,a,b,t,sum
+	// 0,0,1,10,100,11
+	// 1,1,1,10,100,11
+	// 2,0,1,20,100,21
+	// 3,1,1,20,100,21
+	// 4,0,2,10,100,12
+	// 5,1,2,10,100,12
+	// 6,0,2,20,100,22
+	// 7,1,2,20,100,22
+}
+
+// sumObserver reports the sum of all parameter values.
+type sumObserver struct {
+	params ecs.Resource[experiment.Parameters]
+}
+
+func (o *sumObserver) Initialize(w *ecs.World) {
+	o.params = ecs.NewResource[experiment.Parameters](w)
+}
+
+func (o *sumObserver) Update(w *ecs.World) {}
+
+func (o *sumObserver) Header() []string {
+	return []string{"sum"}
+}
+
+func (o *sumObserver) Values(w *ecs.World) []float64 {
+	sum := 0.0
+	for _, v := range o.params.Get().Values {
+		sum += v
+	}
+	return []float64{sum}
+}
+
+// paramsObserver reports the sum of all parameter values, and a random number.
+type paramsObserver struct {
+	sumObserver
+	rand ecs.Resource[resource.Rand]
+}
+
+func (o *paramsObserver) Initialize(w *ecs.World) {
+	o.sumObserver.Initialize(w)
+	o.rand = ecs.NewResource[resource.Rand](w)
+}
+
+func (o *paramsObserver) Header() []string {
+	return []string{"sum", "rand"}
+}
+
+func (o *paramsObserver) Values(w *ecs.World) []float64 {
+	return []float64{o.sumObserver.Values(w)[0], float64(o.rand.Get().Uint64() % 1000)}
+}
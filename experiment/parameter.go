@@ -0,0 +1,81 @@
+package experiment
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Parameter with a name and the values to run.
+type Parameter struct {
+	Name   string    // Name of the parameter.
+	Values []float64 // Values of the parameter.
+}
+
+// Values creates a [Parameter] from a list of values.
+func Values(name string, values ...float64) Parameter {
+	return Parameter{Name: name, Values: values}
+}
+
+// Linear creates a [Parameter] with n values, evenly spaced from start to end (inclusive).
+//
+// Panics if n < 1.
+func Linear(name string, start, end float64, n int) Parameter {
+	if n < 1 {
+		panic(fmt.Sprintf("number of values must be at least 1, got %d", n))
+	}
+	values := make([]float64, n)
+	for i := range n {
+		values[i] = start + (end-start)*fraction(i, n)
+	}
+	return Parameter{Name: name, Values: values}
+}
+
+// Log creates a [Parameter] with n values, logarithmically spaced from start to end (inclusive).
+//
+// Panics if n < 1, or if start or end are not positive.
+func Log(name string, start, end float64, n int) Parameter {
+	if n < 1 {
+		panic(fmt.Sprintf("number of values must be at least 1, got %d", n))
+	}
+	if start <= 0 || end <= 0 {
+		panic(fmt.Sprintf("log range requires positive bounds, got %f and %f", start, end))
+	}
+	values := make([]float64, n)
+	for i := range n {
+		values[i] = start * math.Pow(end/start, fraction(i, n))
+	}
+	return Parameter{Name: name, Values: values}
+}
+
+// fraction of the range for value i out of n.
+func fraction(i, n int) float64 {
+	if n == 1 {
+		return 0
+	}
+	return float64(i) / float64(n-1)
+}
+
+// Parameters is a resource holding the parameter values of the current run.
+//
+// It is added to the world of each run by [Experiment].
+type Parameters struct {
+	Names  []string  // Names of the parameters.
+	Values []float64 // Values of the parameters, in the same order as the names.
+}
+
+// Get returns the value of the parameter with the given name.
+//
+// Panics if there is no such parameter.
+func (p *Parameters) Get(name string) float64 {
+	idx := slices.Index(p.Names, name)
+	if idx < 0 {
+		panic(fmt.Sprintf("parameter '%s' not found", name))
+	}
+	return p.Values[idx]
+}
+
+// Has returns whether there is a parameter with the given name.
+func (p *Parameters) Has(name string) bool {
+	return slices.Contains(p.Names, name)
+}
@@ -0,0 +1,38 @@
+package experiment_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/ark-tools/experiment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinear(t *testing.T) {
+	p := experiment.Linear("a", 0, 1, 5)
+	assert.Equal(t, "a", p.Name)
+	assert.Equal(t, []float64{0, 0.25, 0.5, 0.75, 1}, p.Values)
+
+	p = experiment.Linear("a", 2, 3, 1)
+	assert.Equal(t, []float64{2}, p.Values)
+
+	assert.Panics(t, func() { experiment.Linear("a", 0, 1, 0) })
+}
+
+func TestLog(t *testing.T) {
+	p := experiment.Log("a", 1, 100, 3)
+	assert.InDeltaSlice(t, []float64{1, 10, 100}, p.Values, 1e-9)
+
+	assert.Panics(t, func() { experiment.Log("a", 0, 1, 3) })
+	assert.Panics(t, func() { experiment.Log("a", 1, 10, 0) })
+}
+
+func TestParameters(t *testing.T) {
+	p := experiment.Parameters{
+		Names:  []string{"a", "b"},
+		Values: []float64{1, 2},
+	}
+	assert.Equal(t, 2.0, p.Get("b"))
+	assert.True(t, p.Has("a"))
+	assert.False(t, p.Has("c"))
+	assert.Panics(t, func() { p.Get("c") })
+}
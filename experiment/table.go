@@ -0,0 +1,42 @@
+package experiment
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Table of data collected by an [Experiment].
+//
+// Columns are the run index, the replicate index, the parameter values, the tick
+// of the observation, and finally the observer's columns.
+type Table struct {
+	Header []string    // Column names.
+	Rows   [][]float64 // Data rows.
+}
+
+// WriteCSV writes the table in CSV format, using the given column separator.
+// Uses "," if the separator is empty.
+func (t *Table) WriteCSV(w io.Writer, sep string) error {
+	if sep == "" {
+		sep = ","
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", strings.Join(t.Header, sep)); err != nil {
+		return err
+	}
+	builder := strings.Builder{}
+	for _, row := range t.Rows {
+		builder.Reset()
+		for i, v := range row {
+			builder.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+			if i < len(row)-1 {
+				builder.WriteString(sep)
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", builder.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
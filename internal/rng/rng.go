@@ -0,0 +1,11 @@
+// Package rng provides random number helpers shared by ark-tools packages.
+package rng
+
+// SplitMix64 performs a single step of the SplitMix64 PRNG, with x as its state.
+// It is used to derive well-distributed, decorrelated seeds, also from consecutive inputs.
+func SplitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
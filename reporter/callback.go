@@ -2,6 +2,7 @@ package reporter
 
 import (
 	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
 
@@ -90,3 +91,26 @@ func (s *TableCallback) Finalize(w *ecs.World) {
 	values := s.Observer.Values(w)
 	s.Callback(int(s.step), values)
 }
+
+// TerminationCallback reporter calling a function with the final [resource.Termination] state on finalization.
+//
+// Can be used to record why and when a run terminated.
+type TerminationCallback struct {
+	Callback func(step int, term resource.Termination) // Called with the final tick and termination state.
+	tickRes  ecs.Resource[resource.Tick]
+	termRes  ecs.Resource[resource.Termination]
+}
+
+// Initialize the system
+func (s *TerminationCallback) Initialize(w *ecs.World) {
+	s.tickRes = ecs.NewResource[resource.Tick](w)
+	s.termRes = ecs.NewResource[resource.Termination](w)
+}
+
+// Update the system
+func (s *TerminationCallback) Update(w *ecs.World) {}
+
+// Finalize the system
+func (s *TerminationCallback) Finalize(w *ecs.World) {
+	s.Callback(int(s.tickRes.Get().Tick), *s.termRes.Get())
+}
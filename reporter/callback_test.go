@@ -6,6 +6,7 @@ import (
 
 	"github.com/mlange-42/ark-tools/app"
 	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark-tools/system"
 	"github.com/stretchr/testify/assert"
 )
@@ -97,3 +98,41 @@ func TestTableCallbackFinal(t *testing.T) {
 
 	assert.Equal(t, 1, counter)
 }
+
+func TestTerminationCallbackAddRunning(t *testing.T) {
+	app := app.New(1024)
+	app.AddSystem(&system.FixedTermination{Steps: 10})
+
+	app.Initialize()
+	for range 5 {
+		app.Update()
+	}
+
+	var step int
+	app.AddSystem(&reporter.TerminationCallback{
+		Callback: func(s int, term resource.Termination) { step = s },
+	})
+	app.Run()
+
+	assert.Equal(t, 10, step)
+}
+
+func ExampleTerminationCallback() {
+	// Create a new model.
+	app := app.New(1024)
+
+	// Add a reporter for the termination reason.
+	app.AddSystem(&reporter.TerminationCallback{
+		Callback: func(step int, term resource.Termination) {
+			fmt.Printf("Terminated after %d steps: %s\n", step, term.Reason)
+		},
+	})
+
+	// Add a termination system that ends the simulation.
+	app.AddSystem(&system.FixedTermination{Steps: 3})
+
+	// Run the simulation.
+	app.Run()
+	// Output:
+	// Terminated after 3 steps: reached 3 ticks
+}
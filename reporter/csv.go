@@ -1,6 +1,7 @@
 package reporter
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -25,8 +26,17 @@ type CSV struct {
 	step           int64
 }
 
-// Initialize the system
+// Initialize the system.
+//
+// Panics if the output file can't be created. See [CSV.InitializeE] for an error-returning variant.
 func (s *CSV) Initialize(w *ecs.World) {
+	if err := s.InitializeE(w); err != nil {
+		panic(err)
+	}
+}
+
+// InitializeE initializes the system, and returns an error if the output file can't be created.
+func (s *CSV) InitializeE(w *ecs.World) error {
 	s.Observer.Initialize(w)
 	s.header = s.Observer.Header()
 	if s.UpdateInterval == 0 {
@@ -38,23 +48,33 @@ func (s *CSV) Initialize(w *ecs.World) {
 
 	err := os.MkdirAll(filepath.Dir(s.File), os.ModePerm)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	s.file, err = os.Create(s.File)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	_, err = fmt.Fprintf(s.file, "t%s%s\n", s.Sep, strings.Join(s.header, s.Sep))
 	if err != nil {
-		panic(err)
+		return errors.Join(err, s.file.Close())
 	}
 
 	s.step = 0
+	return nil
 }
 
-// Update the system
+// Update the system.
+//
+// Panics if writing fails. See [CSV.UpdateE] for an error-returning variant.
 func (s *CSV) Update(w *ecs.World) {
+	if err := s.UpdateE(w); err != nil {
+		panic(err)
+	}
+}
+
+// UpdateE updates the system, and returns an error if writing fails.
+func (s *CSV) UpdateE(w *ecs.World) error {
 	s.Observer.Update(w)
 	if s.UpdateInterval == 0 || s.step%int64(s.UpdateInterval) == 0 {
 		values := s.Observer.Values(w)
@@ -68,15 +88,23 @@ func (s *CSV) Update(w *ecs.World) {
 		}
 		_, err := fmt.Fprintf(s.file, "%s\n", s.builder.String())
 		if err != nil {
-			panic(err)
+			return err
 		}
 	}
 	s.step++
+	return nil
 }
 
-// Finalize the system
+// Finalize the system.
+//
+// Panics if the file can't be closed. See [CSV.FinalizeE] for an error-returning variant.
 func (s *CSV) Finalize(w *ecs.World) {
-	if err := s.file.Close(); err != nil {
+	if err := s.FinalizeE(w); err != nil {
 		panic(err)
 	}
 }
+
+// FinalizeE finalizes the system, and returns an error if the file can't be closed.
+func (s *CSV) FinalizeE(w *ecs.World) error {
+	return s.file.Close()
+}
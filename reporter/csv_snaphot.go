@@ -1,6 +1,7 @@
 package reporter
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -24,8 +25,17 @@ type SnapshotCSV struct {
 	step           int64
 }
 
-// Initialize the system
+// Initialize the system.
+//
+// Panics if the output directory can't be created. See [SnapshotCSV.InitializeE] for an error-returning variant.
 func (s *SnapshotCSV) Initialize(w *ecs.World) {
+	if err := s.InitializeE(w); err != nil {
+		panic(err)
+	}
+}
+
+// InitializeE initializes the system, and returns an error if the output directory can't be created.
+func (s *SnapshotCSV) InitializeE(w *ecs.World) error {
 	s.Observer.Initialize(w)
 	s.header = s.Observer.Header()
 	if s.UpdateInterval == 0 {
@@ -38,50 +48,63 @@ func (s *SnapshotCSV) Initialize(w *ecs.World) {
 
 	err := os.MkdirAll(filepath.Dir(fmt.Sprintf(s.FilePattern, 1)), os.ModePerm)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	s.step = 0
+	return nil
 }
 
-// Update the system
+// Update the system.
+//
+// Panics if writing fails. See [SnapshotCSV.UpdateE] for an error-returning variant.
 func (s *SnapshotCSV) Update(w *ecs.World) {
+	if err := s.UpdateE(w); err != nil {
+		panic(err)
+	}
+}
+
+// UpdateE updates the system, and returns an error if writing fails.
+func (s *SnapshotCSV) UpdateE(w *ecs.World) error {
 	s.Observer.Update(w)
 	if s.UpdateInterval == 0 || s.step%int64(s.UpdateInterval) == 0 {
-		file, err := os.Create(fmt.Sprintf(s.FilePattern, s.step))
-		if err != nil {
-			panic(err)
-		}
-		defer func() {
-			err := file.Close()
-			if err != nil {
-				panic(err)
-			}
-		}()
-
-		_, err = fmt.Fprintf(file, "%s\n", strings.Join(s.header, s.Sep))
-		if err != nil {
-			panic(err)
-		}
-
-		values := s.Observer.Values(w)
-		s.builder.Reset()
-		for _, row := range values {
-			for i, v := range row {
-				fmt.Fprint(&s.builder, strconv.FormatFloat(v, 'f', -1, 64))
-				if i < len(row)-1 {
-					fmt.Fprint(&s.builder, s.Sep)
-				}
-			}
-			fmt.Fprint(&s.builder, "\n")
-		}
-		_, err = fmt.Fprint(file, s.builder.String())
-		if err != nil {
-			panic(err)
+		if err := s.write(w); err != nil {
+			return err
 		}
 	}
 	s.step++
+	return nil
 }
 
 // Finalize the system
 func (s *SnapshotCSV) Finalize(w *ecs.World) {}
+
+// write the current snapshot to a new file.
+func (s *SnapshotCSV) write(w *ecs.World) (err error) {
+	file, err := os.Create(fmt.Sprintf(s.FilePattern, s.step))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, file.Close())
+	}()
+
+	_, err = fmt.Fprintf(file, "%s\n", strings.Join(s.header, s.Sep))
+	if err != nil {
+		return err
+	}
+
+	values := s.Observer.Values(w)
+	s.builder.Reset()
+	for _, row := range values {
+		for i, v := range row {
+			fmt.Fprint(&s.builder, strconv.FormatFloat(v, 'f', -1, 64))
+			if i < len(row)-1 {
+				fmt.Fprint(&s.builder, s.Sep)
+			}
+		}
+		fmt.Fprint(&s.builder, "\n")
+	}
+	_, err = fmt.Fprint(file, s.builder.String())
+	return err
+}
@@ -2,6 +2,7 @@ package reporter_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/mlange-42/ark-tools/app"
@@ -11,18 +12,36 @@ import (
 )
 
 func TestSnapshotCSV(t *testing.T) {
+	dir := t.TempDir()
 	app := app.New(1024)
 
 	app.AddSystem(&reporter.SnapshotCSV{
 		Observer:    &ExampleSnapshotObserver{},
-		FilePattern: "../out/test-%06d.csv",
+		FilePattern: filepath.Join(dir, "test-%06d.csv"),
 	})
 	app.AddSystem(&system.FixedTermination{Steps: 100})
 
 	app.Run()
 
-	_, err := os.Stat("../out/test-000000.csv")
+	_, err := os.Stat(filepath.Join(dir, "test-000000.csv"))
 	assert.Nil(t, err)
-	_, err = os.Stat("../out/test-000090.csv")
+	_, err = os.Stat(filepath.Join(dir, "test-000090.csv"))
 	assert.Nil(t, err)
 }
+
+func TestSnapshotCSVError(t *testing.T) {
+	// A regular file used as parent directory makes creating the output fail.
+	parent := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, os.WriteFile(parent, nil, 0644))
+
+	app := app.New(1024)
+
+	app.AddSystem(&reporter.SnapshotCSV{
+		Observer:    &ExampleSnapshotObserver{},
+		FilePattern: filepath.Join(parent, "test-%06d.csv"),
+	})
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+
+	err := app.RunE()
+	assert.ErrorContains(t, err, "initializing system *reporter.SnapshotCSV at tick 0")
+}
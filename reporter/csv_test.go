@@ -2,6 +2,7 @@ package reporter_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/mlange-42/ark-tools/app"
@@ -11,16 +12,34 @@ import (
 )
 
 func TestCSV(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.csv")
 	app := app.New(1024)
 
 	app.AddSystem(&reporter.CSV{
 		Observer: &ExampleObserver{},
-		File:     "../out/test.csv",
+		File:     file,
 	})
 	app.AddSystem(&system.FixedTermination{Steps: 100})
 
 	app.Run()
 
-	_, err := os.Stat("../out/test.csv")
+	_, err := os.Stat(file)
 	assert.Nil(t, err)
 }
+
+func TestCSVError(t *testing.T) {
+	// A regular file used as parent directory makes creating the output fail.
+	parent := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, os.WriteFile(parent, nil, 0644))
+
+	app := app.New(1024)
+
+	app.AddSystem(&reporter.CSV{
+		Observer: &ExampleObserver{},
+		File:     filepath.Join(parent, "test.csv"),
+	})
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+
+	err := app.RunE()
+	assert.ErrorContains(t, err, "initializing system *reporter.CSV at tick 0")
+}
@@ -0,0 +1,184 @@
+package resource
+
+import (
+	"container/heap"
+	"reflect"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// EventID identifies an event scheduled in an [EventCalendar].
+type EventID uint64
+
+// EventCalendar is a resource for scheduling events at future ticks or model times.
+//
+// Events are either callbacks (see [EventCalendar.AtTick] and [EventCalendar.AtTime]),
+// or typed events that are passed to handlers (see [ScheduleAtTick], [ScheduleAtTime] and [OnEvent]).
+// Due events are dispatched by the system [github.com/mlange-42/ark-tools/system.EventDispatcher].
+//
+// Events are dispatched ordered by their due tick or time, and by the order of scheduling for equal due dates.
+// In each tick, events scheduled by tick are dispatched before events scheduled by model time.
+// Events scheduled during dispatch are dispatched in the same tick if they are already due.
+//
+// Scheduled events are not saved by [github.com/mlange-42/ark-tools/app.App.Save].
+type EventCalendar struct {
+	byTick   eventQueue
+	byTime   eventQueue
+	events   map[EventID]*event
+	handlers map[reflect.Type][]any
+	nextID   EventID
+}
+
+// event is an entry in an [EventCalendar].
+type event struct {
+	id       EventID
+	due      float64
+	callback func(w *ecs.World)
+	index    int
+}
+
+// AtTick schedules a callback for the given tick.
+// Returns an ID that can be used to cancel the event.
+func (c *EventCalendar) AtTick(tick int64, callback func(w *ecs.World)) EventID {
+	return c.schedule(&c.byTick, float64(tick), callback)
+}
+
+// AtTime schedules a callback for the given model time (see [ModelTime]).
+// The callback is dispatched in the first tick at or after the given time.
+// Returns an ID that can be used to cancel the event.
+func (c *EventCalendar) AtTime(time float64, callback func(w *ecs.World)) EventID {
+	return c.schedule(&c.byTime, time, callback)
+}
+
+// Cancel a scheduled event.
+// Returns false if there is no such event, e.g. because it was already dispatched.
+func (c *EventCalendar) Cancel(id EventID) bool {
+	e, ok := c.events[id]
+	if !ok {
+		return false
+	}
+	delete(c.events, id)
+	if e.index >= 0 {
+		if c.byTick.contains(e) {
+			heap.Remove(&c.byTick, e.index)
+		} else {
+			heap.Remove(&c.byTime, e.index)
+		}
+	}
+	return true
+}
+
+// Len returns the number of scheduled events.
+func (c *EventCalendar) Len() int {
+	return len(c.events)
+}
+
+// Dispatch all events that are due in the given tick and at the given model time.
+// Returns the number of dispatched events.
+//
+// Called by [github.com/mlange-42/ark-tools/system.EventDispatcher].
+func (c *EventCalendar) Dispatch(w *ecs.World, tick int64, time float64) int {
+	count := c.dispatch(w, &c.byTick, float64(tick))
+	return count + c.dispatch(w, &c.byTime, time)
+}
+
+// Reset removes all scheduled events. Event handlers are kept.
+func (c *EventCalendar) Reset() {
+	c.byTick = c.byTick[:0]
+	c.byTime = c.byTime[:0]
+	c.events = nil
+}
+
+func (c *EventCalendar) schedule(queue *eventQueue, due float64, callback func(w *ecs.World)) EventID {
+	if c.events == nil {
+		c.events = map[EventID]*event{}
+	}
+	c.nextID++
+	e := &event{id: c.nextID, due: due, callback: callback}
+	c.events[e.id] = e
+	heap.Push(queue, e)
+	return e.id
+}
+
+func (c *EventCalendar) dispatch(w *ecs.World, queue *eventQueue, due float64) int {
+	count := 0
+	for len(*queue) > 0 && (*queue)[0].due <= due {
+		e := heap.Pop(queue).(*event)
+		delete(c.events, e.id)
+		e.callback(w)
+		count++
+	}
+	return count
+}
+
+// ScheduleAtTick schedules a typed event for the given tick.
+// At dispatch, the event is passed to all handlers registered for its type via [OnEvent].
+// Returns an ID that can be used to cancel the event.
+func ScheduleAtTick[T any](c *EventCalendar, tick int64, evt T) EventID {
+	return c.AtTick(tick, typedCallback(c, evt))
+}
+
+// ScheduleAtTime schedules a typed event for the given model time.
+// At dispatch, the event is passed to all handlers registered for its type via [OnEvent].
+// Returns an ID that can be used to cancel the event.
+func ScheduleAtTime[T any](c *EventCalendar, time float64, evt T) EventID {
+	return c.AtTime(time, typedCallback(c, evt))
+}
+
+// OnEvent registers a handler for typed events of type T.
+// Handlers are called in the order of their registration.
+func OnEvent[T any](c *EventCalendar, handler func(w *ecs.World, evt T)) {
+	if c.handlers == nil {
+		c.handlers = map[reflect.Type][]any{}
+	}
+	tp := reflect.TypeFor[T]()
+	c.handlers[tp] = append(c.handlers[tp], handler)
+}
+
+// typedCallback creates a callback that passes an event to the handlers for its type.
+func typedCallback[T any](c *EventCalendar, evt T) func(w *ecs.World) {
+	return func(w *ecs.World) {
+		for _, handler := range c.handlers[reflect.TypeFor[T]()] {
+			handler.(func(w *ecs.World, evt T))(w, evt)
+		}
+	}
+}
+
+// eventQueue is a priority queue of events, implementing [heap.Interface].
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].due != q[j].due {
+		return q[i].due < q[j].due
+	}
+	return q[i].id < q[j].id
+}
+
+func (q eventQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *eventQueue) Push(x any) {
+	e := x.(*event)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*q = old[:n-1]
+	return e
+}
+
+// contains returns whether the event is in this queue.
+func (q eventQueue) contains(e *event) bool {
+	return e.index >= 0 && e.index < len(q) && q[e.index] == e
+}
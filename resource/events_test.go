@@ -0,0 +1,71 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type recovery struct {
+	ID int
+}
+
+func TestEventCalendar(t *testing.T) {
+	w := ecs.NewWorld()
+	calendar := resource.EventCalendar{}
+	order := []string{}
+	add := func(name string) func(w *ecs.World) {
+		return func(w *ecs.World) { order = append(order, name) }
+	}
+
+	calendar.AtTick(5, add("a"))
+	calendar.AtTick(3, add("b"))
+	cancelled := calendar.AtTick(3, add("c"))
+	calendar.AtTick(3, add("d"))
+	calendar.AtTime(1.5, add("e"))
+	calendar.AtTick(2, func(w *ecs.World) {
+		calendar.AtTick(2, add("f"))
+		calendar.AtTick(4, add("g"))
+	})
+	assert.Equal(t, 6, calendar.Len())
+
+	assert.True(t, calendar.Cancel(cancelled))
+	assert.False(t, calendar.Cancel(cancelled))
+	assert.Equal(t, 5, calendar.Len())
+
+	assert.Equal(t, 0, calendar.Dispatch(&w, 1, 1))
+	assert.Equal(t, 3, calendar.Dispatch(&w, 2, 2))
+	assert.Equal(t, []string{"f", "e"}, order)
+	assert.Equal(t, 2, calendar.Dispatch(&w, 3, 3))
+	assert.Equal(t, 2, calendar.Dispatch(&w, 5, 5))
+	assert.Equal(t, []string{"f", "e", "b", "d", "g", "a"}, order)
+	assert.Equal(t, 0, calendar.Len())
+
+	calendar.AtTime(10, add("h"))
+	calendar.Reset()
+	assert.Equal(t, 0, calendar.Len())
+	assert.Equal(t, 0, calendar.Dispatch(&w, 20, 20))
+}
+
+func TestEventCalendarTyped(t *testing.T) {
+	w := ecs.NewWorld()
+	calendar := resource.EventCalendar{}
+	recovered := []int{}
+	resource.OnEvent(&calendar, func(w *ecs.World, evt recovery) {
+		recovered = append(recovered, evt.ID)
+	})
+	resource.OnEvent(&calendar, func(w *ecs.World, evt recovery) {
+		recovered = append(recovered, -evt.ID)
+	})
+
+	resource.ScheduleAtTick(&calendar, 10, recovery{ID: 1})
+	id := resource.ScheduleAtTime(&calendar, 5, recovery{ID: 2})
+	resource.ScheduleAtTime(&calendar, 2.5, recovery{ID: 3})
+	resource.ScheduleAtTick(&calendar, 1, "no handler")
+	assert.True(t, calendar.Cancel(id))
+
+	calendar.Dispatch(&w, 10, 10)
+	assert.Equal(t, []int{1, -1, 3, -3}, recovered)
+}
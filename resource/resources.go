@@ -1,16 +1,134 @@
 package resource
 
 import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
 	"math/rand/v2"
 
+	"github.com/mlange-42/ark-tools/internal/rng"
 	"github.com/mlange-42/ark/ecs"
 )
 
 // Rand is a PRNG resource to be used in [System] implementations.
 //
+// The state of the PRNG can be serialized in JSON and binary format, and restored from it.
+// This requires the source to implement [encoding.BinaryMarshaler] and [encoding.BinaryUnmarshaler],
+// like [rand.PCG] does.
+//
 // This resource is provided by [github.com/mlange-42/ark-tools/app.App] per default.
 type Rand struct {
 	rand.Source `json:"-"` // Source to use for PRNGs in [System] implementations.
+	seed        uint64
+}
+
+// NewRand creates a new Rand resource with a [rand.PCG] source, seeded with the given seed.
+func NewRand(seed uint64) Rand {
+	return Rand{
+		Source: rand.NewPCG(0, seed),
+		seed:   seed,
+	}
+}
+
+// Seed returns the seed the PRNG was created with by [NewRand].
+// Can be used to log the seed of a run, for reproducibility.
+func (r Rand) Seed() uint64 {
+	return r.seed
+}
+
+// Stream returns a new, independent PRNG derived from the seed of this PRNG and the given name.
+//
+// Streams are deterministic for a given seed and name, and independent of the draws from this PRNG
+// and from other streams. Systems can use their own named stream, so that adding or removing
+// a stochastic system does not change the random sequences of other systems.
+// Streams should be created during system initialization.
+//
+// Note that [github.com/mlange-42/ark-tools/app.App.Save] only saves the state of the app's main PRNG.
+// To checkpoint a stream, keep it in a resource.
+func (r *Rand) Stream(name string) Rand {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(name))
+	return NewRand(rng.SplitMix64(r.seed ^ rng.SplitMix64(hash.Sum64())))
+}
+
+// randJSON is the JSON representation of [Rand].
+type randJSON struct {
+	Seed  uint64
+	State []byte `json:",omitempty"`
+}
+
+// MarshalJSON returns a JSON representation of the PRNG's seed and state.
+//
+// If the source is nil, only the seed is serialized.
+func (r Rand) MarshalJSON() ([]byte, error) {
+	data := randJSON{Seed: r.seed}
+	if r.Source != nil {
+		var err error
+		if data.State, err = r.sourceState(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(&data)
+}
+
+// UnmarshalJSON restores the PRNG's seed and state from JSON.
+//
+// If there is no state in the data, a new [rand.PCG] source is created from the seed.
+func (r *Rand) UnmarshalJSON(data []byte) error {
+	value := randJSON{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value.State == nil {
+		*r = NewRand(value.Seed)
+		return nil
+	}
+	return r.setState(value.Seed, value.State)
+}
+
+// MarshalBinary returns a binary representation of the PRNG's seed and state.
+func (r Rand) MarshalBinary() ([]byte, error) {
+	state, err := r.sourceState()
+	if err != nil {
+		return nil, err
+	}
+	return append(binary.BigEndian.AppendUint64(nil, r.seed), state...), nil
+}
+
+// UnmarshalBinary restores the PRNG's seed and state from binary data.
+func (r *Rand) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return fmt.Errorf("invalid PRNG data length %d", len(data))
+	}
+	return r.setState(binary.BigEndian.Uint64(data), data[8:])
+}
+
+// sourceState returns the binary state of the source.
+func (r *Rand) sourceState() ([]byte, error) {
+	marshaler, ok := r.Source.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("PRNG source %T does not support serialization", r.Source)
+	}
+	return marshaler.MarshalBinary()
+}
+
+// setState sets the seed and the state of the source.
+// Creates a [rand.PCG] source if the source is nil.
+func (r *Rand) setState(seed uint64, state []byte) error {
+	if r.Source == nil {
+		r.Source = &rand.PCG{}
+	}
+	unmarshaler, ok := r.Source.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("PRNG source %T does not support serialization", r.Source)
+	}
+	if err := unmarshaler.UnmarshalBinary(state); err != nil {
+		return err
+	}
+	r.seed = seed
+	return nil
 }
 
 // Tick is a resource holding the app's time step.
@@ -22,10 +140,36 @@ type Tick struct {
 }
 
 // Termination is a resource holding whether the simulation should terminate after the current step.
+// Systems should terminate the run using [Termination.Stop], to provide a reason.
 //
 // This resource is provided by [github.com/mlange-42/ark-tools/app.App] per default.
 type Termination struct {
-	Terminate bool // Whether the simulation run is finished. Can be set by systems.
+	Terminate bool   // Whether the simulation run is finished. Can be set by systems.
+	Reason    string // Reason for the termination. Optional.
+	Code      int    // Code for the termination reason, with a model-defined meaning. Optional.
+}
+
+// Stop terminates the run with the given reason and code.
+// If the run is already terminated, the first reason and code are kept.
+func (t *Termination) Stop(reason string, code int) {
+	if t.Terminate {
+		return
+	}
+	t.Terminate = true
+	t.Reason = reason
+	t.Code = code
+}
+
+// Interpolation is a resource holding the progress between the last and the next simulation tick.
+// It is updated by the scheduler before each UI update.
+//
+// UI systems can use it for smooth rendering at frame rates higher than the tick rate,
+// by interpolating between the previous and the current model state.
+// The fraction is 1 if the tick rate is not limited, or if the simulation is paused.
+//
+// This resource is provided by [github.com/mlange-42/ark-tools/app.App] per default.
+type Interpolation struct {
+	Fraction float64 // Fraction of the time between the last and the next tick that has passed, in the range [0, 1].
 }
 
 // SelectedEntity is a resource holding the currently selected entity.
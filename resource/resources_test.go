@@ -1,13 +1,18 @@
 package resource_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"testing"
+	"time"
 
 	"math/rand/v2"
 
 	"github.com/mlange-42/ark-tools/app"
 	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
 	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
 )
 
 func ExampleRand() {
@@ -19,6 +24,86 @@ func ExampleRand() {
 	// Output:
 }
 
+func ExampleRand_Seed() {
+	app := app.New(1024).Seed(42)
+
+	src := ecs.GetResource[resource.Rand](&app.World)
+
+	fmt.Println(src.Seed())
+	// Output: 42
+}
+
+func ExampleRand_Stream() {
+	app := app.New(1024).Seed(42)
+
+	src := ecs.GetResource[resource.Rand](&app.World)
+
+	// Typically in the Initialize method of a system.
+	rng := rand.New(src.Stream("movement"))
+	_ = rng.NormFloat64()
+	// Output:
+}
+
+func TestRandStream(t *testing.T) {
+	src := resource.NewRand(42)
+	a1 := src.Stream("a")
+	_ = src.Uint64()
+	a2 := src.Stream("a")
+	b := src.Stream("b")
+
+	assert.Equal(t, a1.Seed(), a2.Seed())
+	assert.Equal(t, a1.Uint64(), a2.Uint64())
+	assert.NotEqual(t, a1.Seed(), b.Seed())
+
+	other := resource.NewRand(43)
+	assert.NotEqual(t, a1.Seed(), other.Stream("a").Seed())
+}
+
+func TestRandJSON(t *testing.T) {
+	src := resource.NewRand(42)
+	_ = src.Uint64()
+
+	data, err := json.Marshal(src)
+	assert.Nil(t, err)
+
+	restored := resource.Rand{}
+	assert.Nil(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, uint64(42), restored.Seed())
+	assert.Equal(t, src.Uint64(), restored.Uint64())
+
+	restored = resource.Rand{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"Seed": 42}`), &restored))
+	expected := resource.NewRand(42)
+	assert.Equal(t, expected.Uint64(), restored.Uint64())
+
+	data, err = json.Marshal(resource.Rand{})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Seed":0}`, string(data))
+
+	_, err = json.Marshal(resource.Rand{Source: constSource{}})
+	assert.ErrorContains(t, err, "PRNG source resource_test.constSource does not support serialization")
+}
+
+// constSource is a PRNG source that does not support serialization.
+type constSource struct{}
+
+func (s constSource) Uint64() uint64 { return 0 }
+
+func TestRandBinary(t *testing.T) {
+	src := resource.NewRand(42)
+	_ = src.Uint64()
+
+	data, err := src.MarshalBinary()
+	assert.Nil(t, err)
+
+	restored := resource.NewRand(1)
+	assert.Nil(t, restored.UnmarshalBinary(data))
+	assert.Equal(t, uint64(42), restored.Seed())
+	assert.Equal(t, src.Uint64(), restored.Uint64())
+
+	assert.NotNil(t, restored.UnmarshalBinary(data[:4]))
+}
+
 func ExampleTick() {
 	app := app.New(1024)
 
@@ -28,6 +113,48 @@ func ExampleTick() {
 	// Output: 0
 }
 
+func ExampleModelTime() {
+	app := app.New(1024)
+
+	modelTime := ecs.GetResource[resource.ModelTime](&app.World)
+	modelTime.Step = 1
+	modelTime.Unit = 24 * time.Hour
+	modelTime.Origin = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	app.AddSystem(&system.FixedTermination{Steps: 100})
+	app.Run()
+
+	fmt.Println(modelTime.Time, modelTime.Date().Format(time.DateOnly), modelTime.DayOfYear(), modelTime.Season())
+	// Output: 100 2025-04-11 101 Spring
+}
+
+func TestModelTime(t *testing.T) {
+	modelTime := resource.ModelTime{Start: 5, Time: 5, Step: 2}
+	modelTime.Advance()
+	assert.Equal(t, 7.0, modelTime.Time)
+	assert.Equal(t, 2.0, modelTime.Elapsed())
+	assert.Panics(t, func() { modelTime.Date() })
+
+	modelTime = resource.ModelTime{
+		Time:   0.5,
+		Unit:   24 * time.Hour,
+		Origin: time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, time.Date(2025, time.December, 31, 12, 0, 0, 0, time.UTC), modelTime.Date())
+	assert.Equal(t, 365, modelTime.DayOfYear())
+	assert.Equal(t, resource.Winter, modelTime.Season())
+
+	modelTime.SouthernHemisphere = true
+	assert.Equal(t, resource.Summer, modelTime.Season())
+
+	modelTime.Time = 200
+	modelTime.SouthernHemisphere = false
+	assert.Equal(t, resource.Summer, modelTime.Season())
+
+	assert.Equal(t, "Autumn", resource.Autumn.String())
+	assert.Equal(t, "Season(7)", resource.Season(7).String())
+}
+
 func ExampleTermination() {
 	app := app.New(1024)
 
@@ -37,6 +164,19 @@ func ExampleTermination() {
 	// Output: false
 }
 
+func ExampleInterpolation() {
+	app := app.New(1024)
+
+	interp := ecs.GetResource[resource.Interpolation](&app.World)
+
+	// In a UI system, interpolate between the previous and the current state.
+	prev, curr := 10.0, 20.0
+	x := prev + interp.Fraction*(curr-prev)
+
+	fmt.Println(x)
+	// Output: 10
+}
+
 func ExampleSelectedEntity() {
 	app := app.New(1024)
 	ecs.AddResource(&app.World, &resource.SelectedEntity{})
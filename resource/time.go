@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+)
+
+// ModelTime is a resource holding the continuous model time, in addition to the integer [Tick].
+//
+// The model time is advanced by Step after each tick, and set to Start on initialization.
+// The current model time should not be modified by user code, as it is managed by the scheduler.
+// The step size can be changed by systems, e.g. for variable time steps.
+//
+// Optionally, model time can be mapped to calendar dates, by giving the Unit and the Origin.
+// See [ModelTime.Date], [ModelTime.DayOfYear] and [ModelTime.Season].
+//
+// This resource is provided by [github.com/mlange-42/ark-tools/app.App] per default, with a Step of 1.
+type ModelTime struct {
+	Time               float64       // Current model time. Managed by the scheduler.
+	Start              float64       // Model time at tick 0.
+	Step               float64       // Model time per tick.
+	Unit               time.Duration // Duration of one unit of model time, like 24h for days. Required for calendar mapping.
+	Origin             time.Time     // Calendar date at model time 0. Required for calendar mapping.
+	SouthernHemisphere bool          // Whether to use seasons of the southern hemisphere.
+}
+
+// Elapsed returns the model time elapsed since the start.
+func (t *ModelTime) Elapsed() float64 {
+	return t.Time - t.Start
+}
+
+// Advance the model time by one step. Called by the scheduler.
+func (t *ModelTime) Advance() {
+	t.Time += t.Step
+}
+
+// Date returns the calendar date of the current model time.
+//
+// Panics if Unit or Origin are not set.
+func (t *ModelTime) Date() time.Time {
+	if t.Unit <= 0 || t.Origin.IsZero() {
+		panic("calendar mapping requires Unit and Origin of the model time")
+	}
+	return t.Origin.Add(time.Duration(t.Time * float64(t.Unit)))
+}
+
+// DayOfYear returns the day of the year of the current model time, in the range [1, 366].
+//
+// Panics if Unit or Origin are not set.
+func (t *ModelTime) DayOfYear() int {
+	return t.Date().YearDay()
+}
+
+// Season returns the meteorological season of the current model time.
+//
+// Panics if Unit or Origin are not set.
+func (t *ModelTime) Season() Season {
+	season := Season((int(t.Date().Month()) / 3) % 4)
+	if t.SouthernHemisphere {
+		season = (season + 2) % 4
+	}
+	return season
+}
+
+// Season of the year, in terms of meteorological seasons.
+// Winter is December to February, spring is March to May, and so on.
+type Season uint8
+
+// Seasons of the year.
+const (
+	Winter Season = iota // Winter season.
+	Spring               // Spring season.
+	Summer               // Summer season.
+	Autumn               // Autumn season.
+)
+
+var seasonNames = [...]string{"Winter", "Spring", "Summer", "Autumn"}
+
+// String returns the name of the season.
+func (s Season) String() string {
+	if int(s) < len(seasonNames) {
+		return seasonNames[s]
+	}
+	return fmt.Sprintf("Season(%d)", s)
+}
@@ -8,10 +8,13 @@ import (
 // CallbackTermination system.
 //
 // Terminates a run according to the return value of a callback function.
+// Can also be used as a [Condition] in [AllTermination] and [AnyTermination].
 //
 // Expects a resource of type [app.Termination].
 type CallbackTermination struct {
 	Callback func(t int64) bool // The callback. ends the simulation when it returns true.
+	Reason   string             // Termination reason. Optional, defaults to "callback condition met".
+	Code     int                // Termination code. Optional.
 	tickRes  ecs.Resource[resource.Tick]
 	termRes  ecs.Resource[resource.Termination]
 }
@@ -24,13 +27,23 @@ func (s *CallbackTermination) Initialize(w *ecs.World) {
 
 // Update the system
 func (s *CallbackTermination) Update(w *ecs.World) {
-	tick := s.tickRes.Get().Tick
-
-	if s.Callback(tick) {
-		term := s.termRes.Get()
-		term.Terminate = true
+	if s.Check(w) {
+		s.termRes.Get().Stop(s.TerminationReason())
 	}
 }
 
 // Finalize the system
 func (s *CallbackTermination) Finalize(w *ecs.World) {}
+
+// Check the callback.
+func (s *CallbackTermination) Check(w *ecs.World) bool {
+	return s.Callback(s.tickRes.Get().Tick)
+}
+
+// TerminationReason returns the reason and code for termination.
+func (s *CallbackTermination) TerminationReason() (string, int) {
+	if s.Reason != "" {
+		return s.Reason, s.Code
+	}
+	return "callback condition met", s.Code
+}
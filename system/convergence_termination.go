@@ -0,0 +1,158 @@
+package system
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// ConvergenceTermination system.
+//
+// Terminates a run when the model reaches a steady state, or leaves a valid range, based on an [observer.Row].
+//
+// The run is considered converged when each selected column changes by at most Tolerance,
+// i.e. its maximum minus its minimum, over the last Window ticks.
+// The run is out of range when any column in Valid leaves its range.
+// The criterion that fired is recorded as the reason and code in the [resource.Termination].
+// Can also be used as a [Condition] in [AllTermination] and [AnyTermination].
+//
+// Panics during initialization if a column is not in the observer's header.
+//
+// Expects a resource of type [app.Termination].
+type ConvergenceTermination struct {
+	Observer  observer.Row          // Observer to get data from.
+	Columns   []string              // Columns to check for convergence. Optional, defaults to all columns.
+	Window    int                   // Number of ticks over which columns must not change more than the tolerance.
+	Tolerance float64               // Maximum change of columns over the window.
+	Valid     map[string][2]float64 // Valid range per column, as minimum and maximum (inclusive). Optional.
+	Code      int                   // Termination code on convergence. Optional.
+	RangeCode int                   // Termination code when leaving the valid range. Optional.
+	termRes   ecs.Resource[resource.Termination]
+	columns   []int
+	valid     []validRange
+	history   [][]float64
+	next      int
+	count     int
+	reason    string
+	code      int
+}
+
+// validRange is a valid range of a column.
+type validRange struct {
+	column   int
+	min, max float64
+}
+
+// Initialize the system
+func (s *ConvergenceTermination) Initialize(w *ecs.World) {
+	s.termRes = ecs.NewResource[resource.Termination](w)
+	s.Observer.Initialize(w)
+	header := s.Observer.Header()
+
+	s.columns = s.columns[:0]
+	if len(s.Columns) == 0 {
+		for i := range header {
+			s.columns = append(s.columns, i)
+		}
+	}
+	for _, col := range s.Columns {
+		s.columns = append(s.columns, columnIndex(header, col))
+	}
+
+	names := make([]string, 0, len(s.Valid))
+	for col := range s.Valid {
+		names = append(names, col)
+	}
+	slices.Sort(names)
+	s.valid = s.valid[:0]
+	for _, col := range names {
+		bounds := s.Valid[col]
+		s.valid = append(s.valid, validRange{columnIndex(header, col), bounds[0], bounds[1]})
+	}
+
+	s.history = make([][]float64, max(s.Window, 1))
+	for i := range s.history {
+		s.history[i] = make([]float64, len(s.columns))
+	}
+	s.next = 0
+	s.count = 0
+	s.reason = ""
+	s.code = 0
+}
+
+// Update the system
+func (s *ConvergenceTermination) Update(w *ecs.World) {
+	if s.Check(w) {
+		s.termRes.Get().Stop(s.TerminationReason())
+	}
+}
+
+// Finalize the system
+func (s *ConvergenceTermination) Finalize(w *ecs.World) {}
+
+// Check whether the observed values converged or left the valid range.
+func (s *ConvergenceTermination) Check(w *ecs.World) bool {
+	s.Observer.Update(w)
+	values := s.Observer.Values(w)
+	header := s.Observer.Header()
+
+	for _, r := range s.valid {
+		if v := values[r.column]; v < r.min || v > r.max {
+			s.reason = fmt.Sprintf("out of range: %s = %g outside [%g, %g]", header[r.column], v, r.min, r.max)
+			s.code = s.RangeCode
+			return true
+		}
+	}
+
+	if s.Window <= 0 {
+		return false
+	}
+	row := s.history[s.next]
+	for i, col := range s.columns {
+		row[i] = values[col]
+	}
+	s.next = (s.next + 1) % len(s.history)
+	s.count = min(s.count+1, len(s.history))
+	if s.count < len(s.history) {
+		return false
+	}
+
+	for i := range s.columns {
+		lo, hi := s.history[0][i], s.history[0][i]
+		for _, row := range s.history[1:] {
+			lo = min(lo, row[i])
+			hi = max(hi, row[i])
+		}
+		if hi-lo > s.Tolerance {
+			return false
+		}
+	}
+
+	names := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		names[i] = header[col]
+	}
+	s.reason = fmt.Sprintf("converged: %s changed by at most %g over %d ticks", strings.Join(names, ", "), s.Tolerance, s.Window)
+	s.code = s.Code
+	return true
+}
+
+// TerminationReason returns the reason and code for termination,
+// depending on the criterion that fired in the last [ConvergenceTermination.Check].
+func (s *ConvergenceTermination) TerminationReason() (string, int) {
+	return s.reason, s.code
+}
+
+// columnIndex returns the index of a column in a header.
+// Panics if the column is not found.
+func columnIndex(header []string, column string) int {
+	idx := slices.Index(header, column)
+	if idx < 0 {
+		panic(fmt.Sprintf("column '%s' not found in observer header", column))
+	}
+	return idx
+}
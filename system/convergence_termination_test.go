@@ -0,0 +1,98 @@
+package system_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+// tickObserver reports the tick, capped at a limit, and the raw tick.
+type tickObserver struct {
+	Limit   int64
+	tickRes ecs.Resource[resource.Tick]
+}
+
+func (o *tickObserver) Initialize(w *ecs.World) {
+	o.tickRes = ecs.NewResource[resource.Tick](w)
+}
+
+func (o *tickObserver) Update(w *ecs.World) {}
+
+func (o *tickObserver) Header() []string {
+	return []string{"capped", "tick"}
+}
+
+func (o *tickObserver) Values(w *ecs.World) []float64 {
+	tick := o.tickRes.Get().Tick
+	return []float64{float64(min(tick, o.Limit)), float64(tick)}
+}
+
+func TestConvergenceTermination(t *testing.T) {
+	app := app.New(1024)
+	app.AddSystem(&system.ConvergenceTermination{
+		Observer:  &tickObserver{Limit: 50},
+		Columns:   []string{"capped"},
+		Window:    10,
+		Tolerance: 0,
+		Code:      1,
+	})
+
+	app.Run()
+	term := app.Termination()
+	assert.Equal(t, int64(60), ecs.GetResource[resource.Tick](&app.World).Tick)
+	assert.Equal(t, "converged: capped changed by at most 0 over 10 ticks", term.Reason)
+	assert.Equal(t, 1, term.Code)
+}
+
+func TestConvergenceTerminationRange(t *testing.T) {
+	app := app.New(1024)
+	app.AddSystem(&system.ConvergenceTermination{
+		Observer:  &tickObserver{Limit: 50},
+		Window:    10,
+		Tolerance: 0,
+		Valid:     map[string][2]float64{"tick": {0, 30}},
+		RangeCode: 2,
+	})
+
+	app.Run()
+	term := app.Termination()
+	assert.Equal(t, int64(32), ecs.GetResource[resource.Tick](&app.World).Tick)
+	assert.Equal(t, "out of range: tick = 31 outside [0, 30]", term.Reason)
+	assert.Equal(t, 2, term.Code)
+}
+
+func TestConvergenceTerminationColumns(t *testing.T) {
+	app := app.New(1024)
+	app.AddSystem(&system.ConvergenceTermination{
+		Observer: &tickObserver{},
+		Columns:  []string{"missing"},
+		Window:   10,
+	})
+	assert.Panics(t, func() { app.Run() })
+}
+
+func ExampleConvergenceTermination() {
+	myApp := app.New(1024)
+
+	myApp.AddSystem(&system.AnyTermination{
+		Conditions: []system.Condition{
+			&system.FixedTermination{Steps: 1000},
+			&system.ConvergenceTermination{
+				Observer:  &tickObserver{Limit: 100},
+				Columns:   []string{"capped"},
+				Window:    20,
+				Tolerance: 0.001,
+			},
+		},
+	})
+
+	myApp.Run()
+	term := myApp.Termination()
+	fmt.Println(term.Reason)
+	// Output: converged: capped changed by at most 0.001 over 20 ticks
+}
@@ -0,0 +1,42 @@
+package system
+
+import (
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// EventDispatcher system.
+//
+// Dispatches due events from the [resource.EventCalendar] in each tick.
+// Events scheduled by model time use the [resource.ModelTime] resource, if present.
+//
+// Adds an empty [resource.EventCalendar] on initialization if there is none.
+// Systems that schedule events during their own initialization should be added after this system.
+// The dispatcher is typically added in stage [github.com/mlange-42/ark-tools/app.StagePreUpdate].
+type EventDispatcher struct {
+	calendarRes ecs.Resource[resource.EventCalendar]
+	tickRes     ecs.Resource[resource.Tick]
+	timeRes     ecs.Resource[resource.ModelTime]
+}
+
+// Initialize the system
+func (s *EventDispatcher) Initialize(w *ecs.World) {
+	s.calendarRes = ecs.NewResource[resource.EventCalendar](w)
+	if !s.calendarRes.Has() {
+		s.calendarRes.Add(&resource.EventCalendar{})
+	}
+	s.tickRes = ecs.NewResource[resource.Tick](w)
+	s.timeRes = ecs.NewResource[resource.ModelTime](w)
+}
+
+// Update the system
+func (s *EventDispatcher) Update(w *ecs.World) {
+	time := 0.0
+	if s.timeRes.Has() {
+		time = s.timeRes.Get().Time
+	}
+	s.calendarRes.Get().Dispatch(w, s.tickRes.Get().Tick, time)
+}
+
+// Finalize the system
+func (s *EventDispatcher) Finalize(w *ecs.World) {}
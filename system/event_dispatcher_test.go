@@ -0,0 +1,56 @@
+package system_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventDispatcher(t *testing.T) {
+	app := app.New(1024)
+	ecs.GetResource[resource.ModelTime](&app.World).Step = 0.5
+
+	app.AddSystem(&system.EventDispatcher{})
+	app.AddSystem(&system.FixedTermination{Steps: 20})
+	app.Initialize()
+
+	calendar := ecs.GetResource[resource.EventCalendar](&app.World)
+	ticks := map[string]int64{}
+	tick := ecs.GetResource[resource.Tick](&app.World)
+	calendar.AtTick(7, func(w *ecs.World) { ticks["tick"] = tick.Tick })
+	calendar.AtTime(3.2, func(w *ecs.World) { ticks["time"] = tick.Tick })
+
+	for app.Update() {
+	}
+	app.Finalize()
+
+	assert.Equal(t, map[string]int64{"tick": 7, "time": 7}, ticks)
+}
+
+type harvest struct {
+	Field int
+}
+
+func ExampleEventDispatcher() {
+	myApp := app.New(1024)
+	myApp.AddSystem(&system.EventDispatcher{}, app.InStage(app.StagePreUpdate))
+	myApp.AddSystem(&system.FixedTermination{Steps: 100})
+	myApp.Initialize()
+
+	calendar := ecs.GetResource[resource.EventCalendar](&myApp.World)
+	resource.OnEvent(calendar, func(w *ecs.World, evt harvest) {
+		tick := ecs.GetResource[resource.Tick](w)
+		fmt.Printf("Harvest field %d at tick %d\n", evt.Field, tick.Tick)
+	})
+	resource.ScheduleAtTick(calendar, 50, harvest{Field: 2})
+	resource.ScheduleAtTick(calendar, 20, harvest{Field: 1})
+
+	myApp.Run()
+	// Output: Harvest field 1 at tick 20
+	// Harvest field 2 at tick 50
+}
@@ -1,6 +1,8 @@
 package system
 
 import (
+	"fmt"
+
 	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
@@ -8,10 +10,13 @@ import (
 // FixedTermination system.
 //
 // Terminates a run after a fixed number of ticks.
+// Can also be used as a [Condition] in [AllTermination] and [AnyTermination].
 //
 // Expects a resource of type [app.Termination].
 type FixedTermination struct {
-	Steps   int64 // Number of simulation ticks to run.
+	Steps   int64  // Number of simulation ticks to run.
+	Reason  string // Termination reason. Optional, defaults to "reached <Steps> ticks".
+	Code    int    // Termination code. Optional.
 	tickRes ecs.Resource[resource.Tick]
 	termRes ecs.Resource[resource.Termination]
 }
@@ -24,13 +29,23 @@ func (s *FixedTermination) Initialize(w *ecs.World) {
 
 // Update the system
 func (s *FixedTermination) Update(w *ecs.World) {
-	tick := s.tickRes.Get().Tick
-
-	if tick+1 >= s.Steps {
-		term := s.termRes.Get()
-		term.Terminate = true
+	if s.Check(w) {
+		s.termRes.Get().Stop(s.TerminationReason())
 	}
 }
 
 // Finalize the system
 func (s *FixedTermination) Finalize(w *ecs.World) {}
+
+// Check whether the number of ticks is reached.
+func (s *FixedTermination) Check(w *ecs.World) bool {
+	return s.tickRes.Get().Tick+1 >= s.Steps
+}
+
+// TerminationReason returns the reason and code for termination.
+func (s *FixedTermination) TerminationReason() (string, int) {
+	if s.Reason != "" {
+		return s.Reason, s.Code
+	}
+	return fmt.Sprintf("reached %d ticks", s.Steps), s.Code
+}
@@ -0,0 +1,85 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// PopulationTermination system.
+//
+// Terminates a run when the number of entities matching a filter falls below a minimum,
+// e.g. on extinction, or exceeds a maximum, e.g. on saturation.
+// Counting entities is cheap, as it only requires to iterate archetypes and tables, not entities.
+// Can also be used as a [Condition] in [AllTermination] and [AnyTermination].
+//
+// To check only in some ticks, add the system with option [github.com/mlange-42/ark-tools/app.WithInterval].
+//
+// Expects a resource of type [app.Termination].
+type PopulationTermination struct {
+	With    []ecs.Comp // Components the counted entities must have. Create with [ecs.C].
+	Without []ecs.Comp // Components the counted entities must not have. Optional.
+	Min     int        // Terminate when the count is below this value. E.g. 1 for termination on extinction.
+	Max     int        // Terminate when the count is above this value. Optional, 0 for no upper threshold.
+	Reason  string     // Termination reason. Optional, defaults to a description of the threshold.
+	Code    int        // Termination code. Optional.
+	Count   int        // Number of matching entities at the last check.
+	filter  ecs.UnsafeFilter
+	termRes ecs.Resource[resource.Termination]
+	reason  string
+}
+
+// Initialize the system
+func (s *PopulationTermination) Initialize(w *ecs.World) {
+	s.termRes = ecs.NewResource[resource.Termination](w)
+	s.filter = ecs.NewUnsafeFilter(w, componentIDs(w, s.With)...)
+	if len(s.Without) > 0 {
+		s.filter = s.filter.Without(componentIDs(w, s.Without)...)
+	}
+	s.reason = ""
+}
+
+// Update the system
+func (s *PopulationTermination) Update(w *ecs.World) {
+	if s.Check(w) {
+		s.termRes.Get().Stop(s.TerminationReason())
+	}
+}
+
+// Finalize the system
+func (s *PopulationTermination) Finalize(w *ecs.World) {}
+
+// Check whether the number of matching entities is out of the thresholds.
+func (s *PopulationTermination) Check(w *ecs.World) bool {
+	query := s.filter.Query()
+	s.Count = query.Count()
+	query.Close()
+
+	if s.Count < s.Min {
+		s.reason = fmt.Sprintf("population of %d below minimum of %d", s.Count, s.Min)
+		return true
+	}
+	if s.Max > 0 && s.Count > s.Max {
+		s.reason = fmt.Sprintf("population of %d above maximum of %d", s.Count, s.Max)
+		return true
+	}
+	return false
+}
+
+// TerminationReason returns the reason and code for termination.
+func (s *PopulationTermination) TerminationReason() (string, int) {
+	if s.Reason != "" {
+		return s.Reason, s.Code
+	}
+	return s.reason, s.Code
+}
+
+// componentIDs returns the IDs of component types, registering them if necessary.
+func componentIDs(w *ecs.World, comps []ecs.Comp) []ecs.ID {
+	ids := make([]ecs.ID, len(comps))
+	for i, c := range comps {
+		ids[i] = ecs.TypeID(w, c.Type())
+	}
+	return ids
+}
@@ -0,0 +1,107 @@
+package system_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type prey struct{}
+
+type infected struct{}
+
+type dead struct{}
+
+// populationSystem adds and removes entities in each tick.
+type populationSystem struct {
+	Add    int
+	Remove int
+	mapper *ecs.Map1[prey]
+	infect *ecs.Map1[infected]
+	filter *ecs.Filter1[prey]
+	buffer []ecs.Entity
+}
+
+func (s *populationSystem) Initialize(w *ecs.World) {
+	s.mapper = ecs.NewMap1[prey](w)
+	s.infect = ecs.NewMap1[infected](w)
+	s.filter = ecs.NewFilter1[prey](w)
+	s.mapper.NewBatchFn(100, nil)
+}
+
+func (s *populationSystem) Update(w *ecs.World) {
+	query := s.filter.Query()
+	for query.Next() {
+		if len(s.buffer) >= s.Remove {
+			query.Close()
+			break
+		}
+		s.buffer = append(s.buffer, query.Entity())
+	}
+	for _, e := range s.buffer {
+		w.RemoveEntity(e)
+	}
+	s.buffer = s.buffer[:0]
+	if s.Add > 0 {
+		s.infect.NewBatchFn(s.Add, nil)
+	}
+}
+
+func (s *populationSystem) Finalize(w *ecs.World) {}
+
+func TestPopulationTermination(t *testing.T) {
+	app := app.New(1024)
+	app.AddSystem(&populationSystem{Remove: 10})
+	term := &system.PopulationTermination{
+		With: []ecs.Comp{ecs.C[prey]()},
+		Min:  1,
+		Code: 1,
+	}
+	app.AddSystem(term)
+
+	app.Run()
+	result := app.Termination()
+	assert.Equal(t, int64(10), ecs.GetResource[resource.Tick](&app.World).Tick)
+	assert.Equal(t, 0, term.Count)
+	assert.Equal(t, resource.Termination{Terminate: true, Reason: "population of 0 below minimum of 1", Code: 1}, result)
+}
+
+func TestPopulationTerminationMax(t *testing.T) {
+	myApp := app.New(1024)
+	myApp.AddSystem(&populationSystem{Add: 7})
+	term := &system.PopulationTermination{
+		With:    []ecs.Comp{ecs.C[infected]()},
+		Without: []ecs.Comp{ecs.C[dead]()},
+		Max:     50,
+	}
+	myApp.AddSystem(term, app.WithInterval(5, 0))
+
+	myApp.Run()
+	result := myApp.Termination()
+	// Checks in ticks 0, 5 and 10.
+	assert.Equal(t, int64(11), ecs.GetResource[resource.Tick](&myApp.World).Tick)
+	assert.Equal(t, 77, term.Count)
+	assert.Equal(t, "population of 77 above maximum of 50", result.Reason)
+}
+
+func ExamplePopulationTermination() {
+	myApp := app.New(1024)
+	myApp.AddSystem(&populationSystem{Remove: 10})
+
+	// Terminate on extinction of prey.
+	myApp.AddSystem(&system.PopulationTermination{
+		With:   []ecs.Comp{ecs.C[prey]()},
+		Min:    1,
+		Reason: "extinction",
+	})
+
+	myApp.Run()
+	term := myApp.Termination()
+	fmt.Println(term.Reason)
+	// Output: extinction
+}
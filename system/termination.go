@@ -0,0 +1,133 @@
+package system
+
+import (
+	"strings"
+
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Condition is a termination condition that can be combined with others
+// via [AllTermination] and [AnyTermination].
+//
+// All termination systems in this package implement Condition.
+// Conditions that are combined should not be added to the app as systems themselves.
+type Condition interface {
+	Initialize(w *ecs.World)          // Initialize the condition.
+	Check(w *ecs.World) bool          // Check whether the condition is met. Called exactly once per tick.
+	TerminationReason() (string, int) // Reason and code for termination when the condition is met.
+}
+
+// AllTermination system.
+//
+// Terminates a run when all of its conditions are met in the same tick.
+// Can itself be used as a [Condition], for nesting.
+//
+// Expects a resource of type [app.Termination].
+type AllTermination struct {
+	Conditions []Condition // Conditions to combine.
+	Reason     string      // Termination reason. Optional, defaults to the reasons of all conditions.
+	Code       int         // Termination code. Optional.
+	termRes    ecs.Resource[resource.Termination]
+}
+
+// Initialize the system
+func (s *AllTermination) Initialize(w *ecs.World) {
+	s.termRes = ecs.NewResource[resource.Termination](w)
+	for _, cond := range s.Conditions {
+		cond.Initialize(w)
+	}
+}
+
+// Update the system
+func (s *AllTermination) Update(w *ecs.World) {
+	if s.Check(w) {
+		s.termRes.Get().Stop(s.TerminationReason())
+	}
+}
+
+// Finalize the system
+func (s *AllTermination) Finalize(w *ecs.World) {}
+
+// Check whether all conditions are met.
+// Checks all conditions, also if one is not met.
+func (s *AllTermination) Check(w *ecs.World) bool {
+	met := len(s.Conditions) > 0
+	for _, cond := range s.Conditions {
+		if !cond.Check(w) {
+			met = false
+		}
+	}
+	return met
+}
+
+// TerminationReason returns the reason and code for termination.
+func (s *AllTermination) TerminationReason() (string, int) {
+	if s.Reason != "" {
+		return s.Reason, s.Code
+	}
+	reasons := make([]string, len(s.Conditions))
+	for i, cond := range s.Conditions {
+		reasons[i], _ = cond.TerminationReason()
+	}
+	return strings.Join(reasons, " and "), s.Code
+}
+
+// AnyTermination system.
+//
+// Terminates a run when any of its conditions is met.
+// Can itself be used as a [Condition], for nesting.
+//
+// Expects a resource of type [app.Termination].
+type AnyTermination struct {
+	Conditions []Condition // Conditions to combine.
+	Reason     string      // Termination reason. Optional, defaults to the reason of the first condition met.
+	Code       int         // Termination code. Optional, defaults to the code of the first condition met.
+	termRes    ecs.Resource[resource.Termination]
+	met        Condition
+}
+
+// Initialize the system
+func (s *AnyTermination) Initialize(w *ecs.World) {
+	s.termRes = ecs.NewResource[resource.Termination](w)
+	for _, cond := range s.Conditions {
+		cond.Initialize(w)
+	}
+}
+
+// Update the system
+func (s *AnyTermination) Update(w *ecs.World) {
+	if s.Check(w) {
+		s.termRes.Get().Stop(s.TerminationReason())
+	}
+}
+
+// Finalize the system
+func (s *AnyTermination) Finalize(w *ecs.World) {}
+
+// Check whether any condition is met.
+// Checks all conditions, also if one is already met.
+func (s *AnyTermination) Check(w *ecs.World) bool {
+	s.met = nil
+	for _, cond := range s.Conditions {
+		if cond.Check(w) && s.met == nil {
+			s.met = cond
+		}
+	}
+	return s.met != nil
+}
+
+// TerminationReason returns the reason and code for termination.
+func (s *AnyTermination) TerminationReason() (string, int) {
+	reason, code := s.Reason, s.Code
+	if s.met != nil {
+		metReason, metCode := s.met.TerminationReason()
+		if reason == "" {
+			reason = metReason
+		}
+		if code == 0 {
+			code = metCode
+		}
+	}
+	return reason, code
+}
@@ -0,0 +1,101 @@
+package system_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllTermination(t *testing.T) {
+	app := app.New(1024)
+
+	app.AddSystem(&system.AllTermination{
+		Conditions: []system.Condition{
+			&system.FixedTermination{Steps: 50},
+			&system.CallbackTermination{
+				Callback: func(t int64) bool { return t%20 == 19 },
+				Reason:   "even",
+			},
+		},
+		Code: 2,
+	})
+
+	app.Run()
+	term := app.Termination()
+
+	tick := ecs.GetResource[resource.Tick](&app.World)
+	assert.Equal(t, 60, int(tick.Tick))
+	assert.Equal(t, resource.Termination{Terminate: true, Reason: "reached 50 ticks and even", Code: 2}, term)
+}
+
+func TestAnyTermination(t *testing.T) {
+	app := app.New(1024)
+
+	app.AddSystem(&system.AnyTermination{
+		Conditions: []system.Condition{
+			&system.FixedTermination{Steps: 50, Code: 1},
+			&system.AllTermination{
+				Conditions: []system.Condition{
+					&system.CallbackTermination{Callback: func(t int64) bool { return t >= 19 }},
+				},
+				Reason: "extinction",
+			},
+		},
+		Code: 3,
+	})
+
+	app.Run()
+	term := app.Termination()
+
+	tick := ecs.GetResource[resource.Tick](&app.World)
+	assert.Equal(t, 20, int(tick.Tick))
+	assert.Equal(t, resource.Termination{Terminate: true, Reason: "extinction", Code: 3}, term)
+
+	empty := system.AllTermination{}
+	assert.False(t, empty.Check(&app.World))
+	anyTerm := system.AnyTermination{}
+	reason, code := anyTerm.TerminationReason()
+	assert.Equal(t, "", reason)
+	assert.Equal(t, 0, code)
+}
+
+func TestTerminationFirstReason(t *testing.T) {
+	app := app.New(1024)
+
+	app.AddSystem(&system.FixedTermination{Steps: 10, Reason: "first", Code: 1})
+	app.AddSystem(&system.FixedTermination{Steps: 10, Reason: "second", Code: 2})
+
+	app.Run()
+	term := app.Termination()
+	assert.Equal(t, "first", term.Reason)
+	assert.Equal(t, 1, term.Code)
+}
+
+func ExampleAnyTermination() {
+	myApp := app.New(1024)
+
+	population := 100
+	myApp.AddSystem(&system.AnyTermination{
+		Conditions: []system.Condition{
+			&system.FixedTermination{Steps: 1000},
+			&system.CallbackTermination{
+				Callback: func(t int64) bool {
+					population -= 10
+					return population <= 0
+				},
+				Reason: "extinction",
+				Code:   1,
+			},
+		},
+	})
+
+	myApp.Run()
+	term := myApp.Termination()
+	fmt.Println(term.Reason, term.Code)
+	// Output: extinction 1
+}
@@ -0,0 +1,90 @@
+package system
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// WallClockTermination system.
+//
+// Terminates a run when a wall-clock time budget is used up, or when a deadline is reached.
+// The run is terminated regularly, so that reporters are finalized and flush their data.
+// On termination, logs the tick reached and, if the total number of steps is given, the projected completion time.
+// Can also be used as a [Condition] in [AllTermination] and [AnyTermination], but then does not log.
+//
+// Expects a resource of type [app.Termination].
+type WallClockTermination struct {
+	Budget   time.Duration // Wall-clock time budget, measured from initialization. Optional.
+	Deadline time.Time     // Wall-clock deadline. Optional.
+	Steps    int64         // Expected total number of ticks, for projecting the completion time. Optional.
+	Reason   string        // Termination reason. Optional, defaults to a description of the budget or deadline.
+	Code     int           // Termination code. Optional.
+	Log      io.Writer     // Writer for the log message on termination. Optional, defaults to [os.Stdout].
+	start    time.Time
+	reason   string
+	tickRes  ecs.Resource[resource.Tick]
+	termRes  ecs.Resource[resource.Termination]
+}
+
+// Initialize the system
+func (s *WallClockTermination) Initialize(w *ecs.World) {
+	s.tickRes = ecs.NewResource[resource.Tick](w)
+	s.termRes = ecs.NewResource[resource.Termination](w)
+	s.start = time.Now()
+	s.reason = ""
+}
+
+// Update the system
+func (s *WallClockTermination) Update(w *ecs.World) {
+	if s.Check(w) {
+		s.termRes.Get().Stop(s.TerminationReason())
+		s.log()
+	}
+}
+
+// Finalize the system
+func (s *WallClockTermination) Finalize(w *ecs.World) {}
+
+// Check whether the budget is used up or the deadline is reached.
+func (s *WallClockTermination) Check(w *ecs.World) bool {
+	now := time.Now()
+	if s.Budget > 0 && now.Sub(s.start) >= s.Budget {
+		s.reason = fmt.Sprintf("wall-clock budget of %s used up", s.Budget)
+		return true
+	}
+	if !s.Deadline.IsZero() && !now.Before(s.Deadline) {
+		s.reason = fmt.Sprintf("wall-clock deadline %s reached", s.Deadline.Format(time.RFC3339))
+		return true
+	}
+	return false
+}
+
+// TerminationReason returns the reason and code for termination.
+func (s *WallClockTermination) TerminationReason() (string, int) {
+	if s.Reason != "" {
+		return s.Reason, s.Code
+	}
+	return s.reason, s.Code
+}
+
+// log writes the log message on termination.
+func (s *WallClockTermination) log() {
+	out := s.Log
+	if out == nil {
+		out = os.Stdout
+	}
+	ticks := s.tickRes.Get().Tick + 1
+	elapsed := time.Since(s.start)
+	reason, _ := s.TerminationReason()
+	fmt.Fprintf(out, "Terminated: %s, at tick %d after %s\n", reason, ticks, elapsed.Round(time.Millisecond))
+	if s.Steps > 0 {
+		projected := time.Duration(float64(elapsed) * float64(s.Steps) / float64(ticks))
+		fmt.Fprintf(out, "Projected completion of %d ticks after %s, at %s\n",
+			s.Steps, projected.Round(time.Millisecond), s.start.Add(projected).Format(time.RFC3339))
+	}
+}
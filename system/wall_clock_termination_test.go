@@ -0,0 +1,64 @@
+package system_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+// sleepSystem sleeps in each update.
+type sleepSystem struct {
+	Duration time.Duration
+}
+
+func (s *sleepSystem) Initialize(w *ecs.World) {}
+func (s *sleepSystem) Update(w *ecs.World)     { time.Sleep(s.Duration) }
+func (s *sleepSystem) Finalize(w *ecs.World)   {}
+
+func TestWallClockTerminationBudget(t *testing.T) {
+	app := app.New(1024)
+	log := bytes.Buffer{}
+	app.AddSystem(&sleepSystem{Duration: time.Millisecond})
+	app.AddSystem(&system.WallClockTermination{
+		Budget: 20 * time.Millisecond,
+		Steps:  100_000,
+		Code:   3,
+		Log:    &log,
+	})
+	app.AddSystem(&system.FixedTermination{Steps: 100_000})
+
+	app.Run()
+	term := app.Termination()
+	tick := ecs.GetResource[resource.Tick](&app.World).Tick
+	assert.Greater(t, tick, int64(1))
+	assert.Less(t, tick, int64(100_000))
+	assert.Equal(t, "wall-clock budget of 20ms used up", term.Reason)
+	assert.Equal(t, 3, term.Code)
+	assert.Contains(t, log.String(), "Terminated: wall-clock budget of 20ms used up, at tick")
+	assert.Contains(t, log.String(), "Projected completion of 100000 ticks after")
+}
+
+func TestWallClockTerminationDeadline(t *testing.T) {
+	app := app.New(1024)
+	log := bytes.Buffer{}
+	deadline := time.Now().Add(20 * time.Millisecond)
+	app.AddSystem(&sleepSystem{Duration: time.Millisecond})
+	app.AddSystem(&system.WallClockTermination{
+		Deadline: deadline,
+		Reason:   "timeout",
+		Log:      &log,
+	})
+
+	app.Run()
+	term := app.Termination()
+	assert.False(t, time.Now().Before(deadline))
+	assert.Equal(t, "timeout", term.Reason)
+	assert.Contains(t, log.String(), "Terminated: timeout, at tick")
+	assert.NotContains(t, log.String(), "Projected")
+}